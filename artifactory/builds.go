@@ -0,0 +1,93 @@
+package artifactory
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const buildsEndpoint = "build"
+
+// Build represents single element of API respond from build endpoint
+type Build struct {
+	URI         string `json:"uri"`
+	LastStarted string `json:"lastStarted"`
+}
+
+// Name returns the build name, decoded from the URI of the build.
+func (b Build) Name() string {
+	name := strings.TrimPrefix(b.URI, "/")
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		return unescaped
+	}
+	return name
+}
+
+type Builds struct {
+	Builds []Build `json:"builds"`
+	NodeId string
+}
+
+// BuildRun represents single element of API respond from build/{buildName} endpoint
+type BuildRun struct {
+	URI     string `json:"uri"`
+	Started string `json:"started"`
+}
+
+type BuildRuns struct {
+	BuildsNumbers []BuildRun `json:"buildsNumbers"`
+}
+
+// FetchBuilds makes the API call to build endpoint and returns the build names
+// with the start time of their latest build.
+func (c *Client) FetchBuilds() (Builds, error) {
+	var builds Builds
+	c.logger.Debug("Fetching builds")
+	resp, err := c.FetchHTTP(buildsEndpoint)
+	if err != nil {
+		// Artifactory answers 404 when there is no build at all
+		if IsNotFound(err) {
+			resp, err = c.FetchHTTP(pingEndpoint)
+			if err != nil {
+				return builds, err
+			}
+			builds.NodeId = resp.NodeId
+			return builds, nil
+		}
+		return builds, err
+	}
+	builds.NodeId = resp.NodeId
+
+	if err := json.Unmarshal(resp.Body, &builds); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal builds respond")
+		return builds, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: buildsEndpoint,
+		}
+	}
+	return builds, nil
+}
+
+// FetchBuildRuns makes the API call to build/{buildName} endpoint and returns
+// the builds of the build name.
+func (c *Client) FetchBuildRuns(buildName string) ([]BuildRun, error) {
+	var runs BuildRuns
+	endpoint := fmt.Sprintf("%s/%s", buildsEndpoint, url.PathEscape(buildName))
+	c.logger.Debug(
+		"Fetching build runs",
+		"build", buildName,
+	)
+	resp, err := c.FetchHTTP(endpoint)
+	if err != nil {
+		return runs.BuildsNumbers, err
+	}
+	if err := json.Unmarshal(resp.Body, &runs); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal build runs respond")
+		return runs.BuildsNumbers, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: endpoint,
+		}
+	}
+	return runs.BuildsNumbers, nil
+}
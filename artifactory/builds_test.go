@@ -0,0 +1,74 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchBuilds(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		switch r.URL.EscapedPath() {
+		case "/api/build":
+			w.Write([]byte(`{"builds":[
+				{"uri":"/app","lastStarted":"2024-01-02T10:00:00.000+0000"},
+				{"uri":"/team%20tool","lastStarted":"2024-01-01T10:00:00.000+0000"}
+			]}`))
+		case "/api/build/team%20tool":
+			w.Write([]byte(`{"uri":"http://localhost/api/build/team%20tool","buildsNumbers":[{"uri":"/2","started":"2024-01-01T10:00:00.000+0000"},{"uri":"/1","started":"2023-12-01T10:00:00.000+0000"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	builds, err := client.FetchBuilds()
+	if err != nil {
+		t.Fatalf("FetchBuilds() error = %v", err)
+	}
+	if len(builds.Builds) != 2 || builds.NodeId != "test-node" {
+		t.Fatalf("Unexpected builds: %+v", builds)
+	}
+	if name := builds.Builds[1].Name(); name != "team tool" {
+		t.Errorf("Build.Name() = %q, want %q", name, "team tool")
+	}
+
+	runs, err := client.FetchBuildRuns("team tool")
+	if err != nil {
+		t.Fatalf("FetchBuildRuns() error = %v", err)
+	}
+	if len(runs) != 2 || runs[0].URI != "/2" {
+		t.Errorf("Unexpected build runs: %+v", runs)
+	}
+}
+
+func TestFetchBuildsNone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		if r.URL.Path == "/api/system/ping" {
+			w.Write([]byte(`OK`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"errors":[{"status":404,"message":"No builds were found"}]}`))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	builds, err := client.FetchBuilds()
+	if err != nil {
+		t.Fatalf("FetchBuilds() error = %v", err)
+	}
+	if len(builds.Builds) != 0 || builds.NodeId != "test-node" {
+		t.Errorf("Unexpected builds: %+v", builds)
+	}
+}
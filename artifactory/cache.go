@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,6 +20,17 @@ type ResponseCache struct {
 	data    map[string]CacheEntry
 	ttl     time.Duration // duration before entries go stale (conf.CacheTTL)
 	timeout time.Duration // request timeout for cached requests (conf.CacheTimeout)
+
+	notModified atomic.Uint64 // responses revalidated with 304 Not Modified
+	bytesSaved  atomic.Uint64 // body bytes not transferred thanks to revalidation
+}
+
+// CacheStats holds the size of the cache and counters about conditional request revalidation.
+type CacheStats struct {
+	Enabled     bool
+	Entries     int
+	NotModified uint64
+	BytesSaved  uint64
 }
 
 func (r *ResponseCache) Prune() int {
@@ -61,6 +73,32 @@ func (r *ResponseCache) GetCachedResponse(key string) (*ApiResponse, bool) {
 	return resp.data, true
 }
 
+// GetValidators returns the cached response for key regardless of its age,
+// so its ETag and Last-Modified validators can be used to revalidate it.
+func (r *ResponseCache) GetValidators(key string) (*ApiResponse, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	resp, exists := r.data[key]
+	if !exists || (resp.data.ETag == "" && resp.data.LastModified == "") {
+		return nil, false
+	}
+	return resp.data, true
+}
+
+// Stats returns the number of entries and the revalidation counters of the cache.
+func (r *ResponseCache) Stats() CacheStats {
+	r.mutex.RLock()
+	entries := len(r.data)
+	r.mutex.RUnlock()
+	return CacheStats{
+		Enabled:     true,
+		Entries:     entries,
+		NotModified: r.notModified.Load(),
+		BytesSaved:  r.bytesSaved.Load(),
+	}
+}
+
 func (r *ResponseCache) SetCachedResponse(key string, response *ApiResponse) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
@@ -138,6 +176,26 @@ func (c *Cached) CacheResponse(response *ApiResponse) {
 	}
 }
 
+// Revalidated refreshes the cache entry after the server answered with
+// 304 Not Modified and records the bytes that did not have to be transferred.
+func (c *Cached) Revalidated(response *ApiResponse) {
+	if c.responseCache == nil {
+		return
+	}
+	c.logger.Debug("Revalidated cached response for key", "key", c.cacheKey)
+	c.responseCache.notModified.Add(1)
+	c.responseCache.bytesSaved.Add(uint64(len(response.Body)))
+	c.responseCache.SetCachedResponse(c.cacheKey, response)
+}
+
+// GetValidators returns the cached response holding validators for a conditional request.
+func (c *Cached) GetValidators() (*ApiResponse, bool) {
+	if c.responseCache != nil {
+		return c.responseCache.GetValidators(c.cacheKey)
+	}
+	return nil, false
+}
+
 func (c *Cached) GetCachedResponse() (*ApiResponse, bool) {
 	if c.responseCache != nil {
 		c.logger.Debug("Getting cached response for key", "key", c.cacheKey)
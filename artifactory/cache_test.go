@@ -2,6 +2,9 @@ package artifactory
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -190,3 +193,56 @@ func TestCachedChannels(t *testing.T) {
 		}
 	})
 }
+
+func TestConditionalRevalidation(t *testing.T) {
+	const etag = `"storageinfo-v1"`
+	const body = `{"binariesSummary":{}}`
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.UseCache = true
+	client := NewClient(conf)
+
+	first, err := client.FetchHTTP("storageinfo")
+	if err != nil {
+		t.Fatalf("FetchHTTP() error = %v", err)
+	}
+	if first.ETag != etag {
+		t.Errorf("Response.ETag = %s, want %s", first.ETag, etag)
+	}
+
+	second, err := client.FetchHTTP("storageinfo")
+	if err != nil {
+		t.Fatalf("FetchHTTP() error = %v", err)
+	}
+	if string(second.Body) != body {
+		t.Errorf("Revalidated body = %s, want %s", string(second.Body), body)
+	}
+	if second.NodeId != "test-node" {
+		t.Errorf("Revalidated NodeId = %s, want test-node", second.NodeId)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Expected 2 requests to the server, got %d", n)
+	}
+
+	stats := client.CacheStats()
+	if stats.NotModified != 1 {
+		t.Errorf("CacheStats.NotModified = %d, want 1", stats.NotModified)
+	}
+	if stats.BytesSaved != uint64(len(body)) {
+		t.Errorf("CacheStats.BytesSaved = %d, want %d", stats.BytesSaved, len(body))
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/peimanja/artifactory_exporter/config"
@@ -21,11 +22,26 @@ type Client struct {
 	OptionalMetrics        config.OptionalMetrics
 	accessFederationTarget string
 	client                 *http.Client
+	aqlClient              *http.Client
+	aqlTimeout             time.Duration
+	upstreamClient         *http.Client
 	logger                 *slog.Logger
 	responseCache          *ResponseCache
+	lastPayloads           *payloadRecorder
+	storageInfoTracker     storageInfoTracker
+
+	// ctx is cancelled by Close, aborting the requests in flight.
+	ctx            context.Context
+	stopBackground context.CancelFunc
+	background     sync.WaitGroup
+	closeOnce      sync.Once
 }
 
+// cachePruneInterval is how often expired entries are removed from the ResponseCache.
+const cachePruneInterval = 300 * time.Second
+
 // NewClient returns an initialized Artifactory HTTP Client.
+// Background goroutines started by the client are stopped by Close.
 func NewClient(conf *config.Config) *Client {
 	tr := &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: !conf.ArtiSSLVerify}}
 	client := &http.Client{
@@ -33,26 +49,61 @@ func NewClient(conf *config.Config) *Client {
 		Transport: tr,
 	}
 	responseCache := NewResponseCache(conf.UseCache, conf.CacheTTL, conf.CacheTimeout)
-	logger := conf.Logger
-	if responseCache != nil {
-		go func() {
-			for {
-				time.Sleep(300 * time.Second)
-				n := responseCache.Prune()
-				logger.Debug("Pruned ResponseCache", "removed_items", n)
-			}
-		}()
-	}
-	return &Client{
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Client{
 		URI:                    conf.ArtiScrapeURI,
 		authMethod:             conf.Credentials.AuthMethod,
 		cred:                   *conf.Credentials,
 		OptionalMetrics:        conf.ExporterRuntimeConfig.OptionalMetrics,
 		accessFederationTarget: conf.AccessFederationTarget,
 		client:                 client,
-		logger:                 logger,
+		aqlClient:              &http.Client{Transport: tr},
+		aqlTimeout:             conf.ArtiAQLTimeout,
+		upstreamClient:         newUpstreamClient(conf.RemoteRepoURLSSLVerify, conf.RemoteRepoURLCheckTimeout),
+		logger:                 conf.Logger,
 		responseCache:          responseCache,
+		lastPayloads:           newPayloadRecorder(conf.DebugLastScrape),
+		ctx:                    ctx,
+		stopBackground:         cancel,
+	}
+	if responseCache != nil {
+		c.background.Add(1)
+		go c.pruneCache(ctx, cachePruneInterval)
+	}
+	if interval := conf.ExporterRuntimeConfig.StorageInfoRefreshInterval; interval > 0 {
+		c.background.Add(1)
+		go c.refreshStorageInfo(ctx, interval)
 	}
+	return c
+}
+
+// pruneCache periodically removes expired entries from the ResponseCache until ctx is cancelled.
+func (c *Client) pruneCache(ctx context.Context, interval time.Duration) {
+	defer c.background.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Debug("Stopping ResponseCache pruner")
+			return
+		case <-ticker.C:
+			n := c.responseCache.Prune()
+			c.logger.Debug("Pruned ResponseCache", "removed_items", n)
+		}
+	}
+}
+
+// Close stops the background goroutines of the client, aborts the requests
+// in flight, waits for the goroutines to exit and releases idle connections. It is safe to call Close more than once.
+func (c *Client) Close() {
+	c.closeOnce.Do(func() {
+		c.stopBackground()
+		c.background.Wait()
+		c.client.CloseIdleConnections()
+		c.aqlClient.CloseIdleConnections()
+		c.upstreamClient.CloseIdleConnections()
+	})
 }
 
 func (c *Client) GetAccessFederationTarget() string {
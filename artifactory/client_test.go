@@ -3,8 +3,10 @@ package artifactory
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
 	"testing"
 	"time"
 
@@ -18,6 +20,7 @@ func createTestConfig() *config.Config {
 		ArtiScrapeURI:         "http://localhost:8081/artifactory",
 		ArtiSSLVerify:         false,
 		ArtiTimeout:           5 * time.Second,
+		ArtiAQLTimeout:        time.Minute,
 		UseCache:              false,
 		CacheTTL:              5 * time.Minute,
 		CacheTimeout:          30 * time.Second,
@@ -70,6 +73,52 @@ func TestNewClientWithCache(t *testing.T) {
 	}
 }
 
+func TestClientClose(t *testing.T) {
+	// waitForGoroutines polls until the goroutine count drops to want, since
+	// exiting goroutines are not accounted for immediately.
+	waitForGoroutines := func(want int) int {
+		deadline := time.Now().Add(2 * time.Second)
+		got := runtime.NumGoroutine()
+		for got > want && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+			got = runtime.NumGoroutine()
+		}
+		return got
+	}
+
+	t.Run("Stops cache pruner", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+		conf := createTestConfig()
+		conf.UseCache = true
+		client := NewClient(conf)
+		if runtime.NumGoroutine() <= before {
+			t.Error("Expected cache pruner goroutine to be started")
+		}
+
+		client.Close()
+		if got := waitForGoroutines(before); got > before {
+			t.Errorf("Goroutines after Close() = %d, want <= %d", got, before)
+		}
+	})
+
+	t.Run("Without cache", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+		client := NewClient(createTestConfig())
+		client.Close()
+		if got := waitForGoroutines(before); got > before {
+			t.Errorf("Goroutines after Close() = %d, want <= %d", got, before)
+		}
+	})
+
+	t.Run("Close is idempotent", func(t *testing.T) {
+		conf := createTestConfig()
+		conf.UseCache = true
+		client := NewClient(conf)
+		client.Close()
+		client.Close()
+	})
+}
+
 func TestGetAccessFederationTarget(t *testing.T) {
 	conf := createTestConfig()
 	conf.AccessFederationTarget = "https://example.com"
@@ -440,3 +489,85 @@ func TestClientHTTPHeaders(t *testing.T) {
 		t.Error("User-Agent header should not be empty")
 	}
 }
+
+func TestStreamAQL(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/search/aql" || r.Method != http.MethodPost {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		calls++
+		if calls > 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors":[{"status":400,"message":"Failed to parse query"}]}`))
+			return
+		}
+		w.Write([]byte(`{"results":[{"name":"a"}]}`))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.UseCache = true
+	client := NewClient(conf)
+	defer client.Close()
+
+	body, err := client.StreamAQL(context.Background(), []byte(`items.find()`))
+	if err != nil {
+		t.Fatalf("StreamAQL() error = %v", err)
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil || string(data) != `{"results":[{"name":"a"}]}` {
+		t.Errorf("StreamAQL() body = %q, %v", data, err)
+	}
+
+	// Streamed responses are never cached, so a failed query is not hidden by a cached answer.
+	if _, err := client.StreamAQL(context.Background(), []byte(`items.find()`)); err == nil {
+		t.Error("Expected StreamAQL() to fail on an error status")
+	}
+}
+
+func TestStreamAQLTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The results take longer to be read than the timeout of other requests
+		w.Write([]byte(`{"results":[`))
+		w.(http.Flusher).Flush()
+		select {
+		case <-time.After(300 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(`]}`))
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	conf.ArtiTimeout = 100 * time.Millisecond
+	client := NewClient(conf)
+	defer client.Close()
+
+	body, err := client.StreamAQL(context.Background(), []byte(`items.find()`))
+	if err != nil {
+		t.Fatalf("StreamAQL() error = %v", err)
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil || string(data) != `{"results":[]}` {
+		t.Errorf("StreamAQL() body = %q, %v", data, err)
+	}
+
+	conf.ArtiAQLTimeout = 100 * time.Millisecond
+	client = NewClient(conf)
+	defer client.Close()
+	body, err = client.StreamAQL(context.Background(), []byte(`items.find()`))
+	if err != nil {
+		t.Fatalf("StreamAQL() error = %v", err)
+	}
+	_, err = io.ReadAll(body)
+	body.Close()
+	if err == nil {
+		t.Error("Expected reading the results to fail after the AQL timeout")
+	}
+}
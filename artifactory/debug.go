@@ -0,0 +1,163 @@
+package artifactory
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	redactedValue   = "<redacted>"
+	maxPayloadBytes = 64 * 1024
+	// maxPayloads bounds the number of endpoints kept, the oldest payload is
+	// dropped first.
+	maxPayloads = 256
+)
+
+// sensitiveKeys are substrings of JSON keys whose values are never exposed in recorded payloads.
+var sensitiveKeys = []string{"password", "passphrase", "token", "secret", "apikey", "api_key", "privatekey", "private_key", "email"}
+
+// Payload is a redacted copy of the last response received from an endpoint.
+type Payload struct {
+	Method    string    `json:"method"`
+	Endpoint  string    `json:"endpoint"`
+	NodeId    string    `json:"node_id,omitempty"`
+	Time      time.Time `json:"time"`
+	Body      string    `json:"body"`
+	Truncated bool      `json:"truncated,omitempty"`
+}
+
+// payloadRecorder keeps the last redacted payload per endpoint since the
+// start of the last scrape for debugging.
+type payloadRecorder struct {
+	mutex    sync.Mutex
+	payloads map[string]Payload
+}
+
+func newPayloadRecorder(enabled bool) *payloadRecorder {
+	if !enabled {
+		return nil
+	}
+	return &payloadRecorder{payloads: make(map[string]Payload)}
+}
+
+func (p *payloadRecorder) record(method string, endpoint string, response *ApiResponse) {
+	if p == nil {
+		return
+	}
+	body := string(redactPayload(response.Body))
+	truncated := len(body) > maxPayloadBytes
+	if truncated {
+		limit := maxPayloadBytes
+		for limit > 0 && !utf8.RuneStart(body[limit]) {
+			limit--
+		}
+		body = body[:limit]
+	}
+	key := method + " " + endpoint
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, exists := p.payloads[key]; !exists && len(p.payloads) >= maxPayloads {
+		oldest := ""
+		for k, payload := range p.payloads {
+			if oldest == "" || payload.Time.Before(p.payloads[oldest].Time) {
+				oldest = k
+			}
+		}
+		delete(p.payloads, oldest)
+	}
+	p.payloads[key] = Payload{
+		Method:    method,
+		Endpoint:  endpoint,
+		NodeId:    response.NodeId,
+		Time:      time.Now(),
+		Body:      body,
+		Truncated: truncated,
+	}
+}
+
+// reset drops the recorded payloads.
+func (p *payloadRecorder) reset() {
+	if p == nil {
+		return
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	clear(p.payloads)
+}
+
+func (p *payloadRecorder) list() []Payload {
+	if p == nil {
+		return nil
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	payloads := make([]Payload, 0, len(p.payloads))
+	for _, payload := range p.payloads {
+		payloads = append(payloads, payload)
+	}
+	sort.Slice(payloads, func(i, j int) bool {
+		return payloads[i].Endpoint < payloads[j].Endpoint
+	})
+	return payloads
+}
+
+// redactPayload replaces the values of sensitive keys in a JSON body.
+// Bodies which are not JSON (e.g. system/ping or OpenMetrics) are returned unchanged.
+func redactPayload(body []byte) []byte {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return body
+	}
+	var redacted bytes.Buffer
+	encoder := json.NewEncoder(&redacted)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(redactValue(doc)); err != nil {
+		return body
+	}
+	return bytes.TrimSuffix(redacted.Bytes(), []byte("\n"))
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitiveKey(key) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// LastPayloads returns the redacted payloads of the last response per endpoint.
+// It is empty unless recording was enabled in the configuration.
+func (c *Client) LastPayloads() []Payload {
+	return c.lastPayloads.list()
+}
+
+// ResetLastPayloads drops the recorded payloads, at the start of a scrape.
+func (c *Client) ResetLastPayloads() {
+	c.lastPayloads.reset()
+}
@@ -0,0 +1,111 @@
+package artifactory
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRedactPayload(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:        "Sensitive keys are redacted",
+			body:        `[{"name":"admin","email":"admin@example.com","password":"hunter2","realm":"internal"}]`,
+			contains:    []string{`"name": "admin"`, `"realm": "internal"`, `"email": "<redacted>"`, `"password": "<redacted>"`},
+			notContains: []string{"hunter2", "admin@example.com"},
+		},
+		{
+			name:        "Nested keys are redacted",
+			body:        `{"tokens":[{"token_id":"abc","refresh_token":"xyz"}],"repoKey":"libs-release"}`,
+			contains:    []string{`"repoKey": "libs-release"`, `"tokens": "<redacted>"`},
+			notContains: []string{"xyz"},
+		},
+		{
+			name:     "Non JSON bodies are kept",
+			body:     "OK",
+			contains: []string{"OK"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(redactPayload([]byte(tt.body)))
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("redactPayload() = %s, want it to contain %s", got, want)
+				}
+			}
+			for _, unwanted := range tt.notContains {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("redactPayload() = %s, must not contain %s", got, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestPayloadRecorder(t *testing.T) {
+	if newPayloadRecorder(false) != nil {
+		t.Error("Expected recorder to be nil when disabled")
+	}
+
+	var disabled *payloadRecorder
+	disabled.record("GET", "http://localhost/api/system/ping", &ApiResponse{Body: []byte("OK")})
+	if len(disabled.list()) != 0 {
+		t.Error("Expected disabled recorder to hold no payloads")
+	}
+
+	recorder := newPayloadRecorder(true)
+	recorder.record("GET", "http://localhost/api/system/version", &ApiResponse{Body: []byte(`{"version":"7.77.3"}`), NodeId: "node-1"})
+	recorder.record("GET", "http://localhost/api/system/ping", &ApiResponse{Body: []byte("OK")})
+	recorder.record("GET", "http://localhost/api/system/ping", &ApiResponse{Body: []byte("OK")})
+
+	payloads := recorder.list()
+	if len(payloads) != 2 {
+		t.Fatalf("Expected 2 payloads, got %d", len(payloads))
+	}
+	if payloads[0].Endpoint != "http://localhost/api/system/ping" {
+		t.Errorf("Expected payloads sorted by endpoint, got %s first", payloads[0].Endpoint)
+	}
+	if payloads[1].NodeId != "node-1" {
+		t.Errorf("Payload NodeId = %s, want node-1", payloads[1].NodeId)
+	}
+}
+
+func TestPayloadRecorderBounds(t *testing.T) {
+	recorder := newPayloadRecorder(true)
+	for i := 0; i < maxPayloads+10; i++ {
+		recorder.record("GET", fmt.Sprintf("http://localhost/api/security/users/user-%d", i), &ApiResponse{Body: []byte("{}")})
+	}
+	payloads := recorder.list()
+	if len(payloads) != maxPayloads {
+		t.Fatalf("Expected %d payloads, got %d", maxPayloads, len(payloads))
+	}
+	for _, payload := range payloads {
+		if payload.Endpoint == "http://localhost/api/security/users/user-0" {
+			t.Error("Expected the oldest payload to be dropped")
+		}
+	}
+
+	// A multi-byte character straddling the limit is not split
+	recorder.record("GET", "http://localhost/api/large", &ApiResponse{Body: []byte(strings.Repeat("a", maxPayloadBytes-1) + "é")})
+	for _, payload := range recorder.list() {
+		if payload.Endpoint != "http://localhost/api/large" {
+			continue
+		}
+		if !payload.Truncated || len(payload.Body) != maxPayloadBytes-1 || !utf8.ValidString(payload.Body) {
+			t.Errorf("Unexpected truncated body of %d bytes, truncated %v", len(payload.Body), payload.Truncated)
+		}
+	}
+
+	recorder.reset()
+	if len(recorder.list()) != 0 {
+		t.Error("Expected no payloads after reset")
+	}
+}
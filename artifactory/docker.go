@@ -0,0 +1,50 @@
+package artifactory
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DockerLayer represents a layer referenced by a Docker image manifest
+type DockerLayer struct {
+	Digest string  `json:"digest"`
+	Size   float64 `json:"size"`
+}
+
+// DockerManifest represents the manifest.json of a Docker tag
+type DockerManifest struct {
+	Layers []DockerLayer `json:"layers"`
+	NodeId string        `json:"-"`
+}
+
+// FetchDockerManifest downloads the manifest.json stored in the folder of a
+// Docker tag, e.g. <image>/<tag>, and returns DockerManifest
+func (c *Client) FetchDockerManifest(repoKey string, folder string) (DockerManifest, error) {
+	var manifest DockerManifest
+	segments := strings.Split(folder, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	fullPath := fmt.Sprintf("%s/%s/%s/manifest.json", c.URI, url.PathEscape(repoKey), strings.Join(segments, "/"))
+	c.logger.Debug(
+		"Fetching Docker manifest",
+		"repo", repoKey,
+		"path", folder,
+	)
+	resp, err := c.makeCachedRequest(http.MethodGet, fullPath, nil, nil)
+	if err != nil {
+		return manifest, err
+	}
+	manifest.NodeId = resp.NodeId
+	if err := json.Unmarshal(resp.Body, &manifest); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal Docker manifest respond")
+		return manifest, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: fullPath,
+		}
+	}
+	return manifest, nil
+}
@@ -1,6 +1,9 @@
 package artifactory
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // UnmarshalError is a custom Error type for unmarshal API respond body error
 type UnmarshalError struct {
@@ -34,3 +37,9 @@ func (e *APIError) apiEndpoint() string {
 func (e *APIError) apiStatus() int {
 	return e.status
 }
+
+// IsNotFound reports whether err is an API error for a missing resource (404).
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.status == 404
+}
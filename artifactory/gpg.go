@@ -0,0 +1,57 @@
+package artifactory
+
+import (
+	"encoding/json"
+)
+
+const (
+	gpgSigningKeyEndpoint = "gpg/key/public"
+	trustedKeysEndpoint   = "security/keys/trusted"
+)
+
+// TrustedKey represents single element of API respond from trusted keys endpoint
+type TrustedKey struct {
+	Kid         string `json:"kid"`
+	Fingerprint string `json:"fingerprint"`
+	Alias       string `json:"alias"`
+	Key         string `json:"key"`
+}
+
+// GPGKeys holds the ASCII armored public GPG signing key, empty when no
+// signing key is configured, and the trusted keys
+type GPGKeys struct {
+	SigningKey  string
+	TrustedKeys []TrustedKey `json:"keys"`
+	NodeId      string
+}
+
+// FetchGPGKeys makes the API calls to the GPG signing key and trusted keys endpoints
+// and returns GPGKeys
+func (c *Client) FetchGPGKeys() (GPGKeys, error) {
+	var keys GPGKeys
+	c.logger.Debug("Fetching GPG signing key")
+	resp, err := c.FetchHTTP(gpgSigningKeyEndpoint)
+	if err != nil {
+		if !IsNotFound(err) {
+			return keys, err
+		}
+		c.logger.Debug("No GPG signing key configured")
+	} else {
+		keys.SigningKey = string(resp.Body)
+	}
+
+	c.logger.Debug("Fetching trusted keys")
+	resp, err = c.FetchHTTP(trustedKeysEndpoint)
+	if err != nil {
+		return keys, err
+	}
+	keys.NodeId = resp.NodeId
+	if err := json.Unmarshal(resp.Body, &keys); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal trusted keys response")
+		return keys, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: trustedKeysEndpoint,
+		}
+	}
+	return keys, nil
+}
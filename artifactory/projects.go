@@ -0,0 +1,106 @@
+package artifactory
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+const projectsEndpoint = "access/api/v1/projects"
+
+// Project represents single element of API respond from Access projects endpoint
+type Project struct {
+	Key               string `json:"project_key"`
+	DisplayName       string `json:"display_name"`
+	StorageQuotaBytes int64  `json:"storage_quota_bytes"`
+	SoftLimit         bool   `json:"soft_limit"`
+}
+
+type Projects struct {
+	Projects []Project
+	NodeId   string
+}
+
+// ProjectMember represents a user or group and the roles it holds in a project
+type ProjectMember struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+}
+
+type ProjectMembers struct {
+	Members []ProjectMember `json:"members"`
+}
+
+// FetchProjects makes the API call to Access projects endpoint and returns []Project
+func (c *Client) FetchProjects() (Projects, error) {
+	var projects Projects
+	c.logger.Debug("Fetching projects")
+
+	// Use ping endpoint to retrieve nodeID, since this is not returned by access API
+	resp, err := c.FetchHTTP(pingEndpoint)
+	if err != nil {
+		return projects, err
+	}
+	projects.NodeId = resp.NodeId
+
+	resp, err = c.FetchAccessHTTP(projectsEndpoint)
+	if err != nil {
+		return projects, err
+	}
+	if err := json.Unmarshal(resp.Body, &projects.Projects); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal projects respond")
+		return projects, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: projectsEndpoint,
+		}
+	}
+	return projects, nil
+}
+
+// FetchProjectMembers makes the API call to Access projects/{projectKey}/{memberType} endpoint
+// and returns the members of the project. memberType is either "users" or "groups".
+func (c *Client) FetchProjectMembers(projectKey string, memberType string) ([]ProjectMember, error) {
+	var members ProjectMembers
+	endpoint := fmt.Sprintf("%s/%s/%s", projectsEndpoint, url.PathEscape(projectKey), memberType)
+	c.logger.Debug(
+		"Fetching project members",
+		"project", projectKey,
+		"type", memberType,
+	)
+	resp, err := c.FetchAccessHTTP(endpoint)
+	if err != nil {
+		return members.Members, err
+	}
+	if err := json.Unmarshal(resp.Body, &members); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal project members respond")
+		return members.Members, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: endpoint,
+		}
+	}
+	return members.Members, nil
+}
+
+// FetchProjectRepositories makes the API call to repositories endpoint filtered by project
+// and returns the repositories assigned to the project.
+func (c *Client) FetchProjectRepositories(projectKey string) (Repositories, error) {
+	var repositories Repositories
+	endpoint := fmt.Sprintf("%s?project=%s", repositoriesEndpoint, url.QueryEscape(projectKey))
+	c.logger.Debug(
+		"Fetching project repositories",
+		"project", projectKey,
+	)
+	resp, err := c.FetchHTTP(endpoint)
+	if err != nil {
+		return repositories, err
+	}
+	repositories.NodeId = resp.NodeId
+	if err := json.Unmarshal(resp.Body, &repositories.Repositories); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal project repositories respond")
+		return repositories, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: endpoint,
+		}
+	}
+	return repositories, nil
+}
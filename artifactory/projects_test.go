@@ -0,0 +1,71 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchProjects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		switch r.URL.Path {
+		case "/api/system/ping":
+			w.Write([]byte(`OK`))
+		case "/access/api/v1/projects":
+			w.Write([]byte(`[
+				{"project_key":"team1","display_name":"Team 1","storage_quota_bytes":1073741824,"soft_limit":false},
+				{"project_key":"team2","display_name":"Team 2","storage_quota_bytes":-1}
+			]`))
+		case "/access/api/v1/projects/team1/users":
+			w.Write([]byte(`{"members":[{"name":"alice","roles":["Project Admin","Developer"]},{"name":"bob","roles":["Developer"]}]}`))
+		case "/api/repositories":
+			if r.URL.Query().Get("project") != "team1" {
+				t.Errorf("Expected project query parameter team1, got %q", r.URL.Query().Get("project"))
+			}
+			w.Write([]byte(`[{"key":"team1-maven-local","type":"LOCAL","packageType":"Maven"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	projects, err := client.FetchProjects()
+	if err != nil {
+		t.Fatalf("FetchProjects() error = %v", err)
+	}
+	if len(projects.Projects) != 2 {
+		t.Fatalf("Expected 2 projects, got %d", len(projects.Projects))
+	}
+	if projects.NodeId != "test-node" {
+		t.Errorf("Projects.NodeId = %s, want test-node", projects.NodeId)
+	}
+	if p := projects.Projects[0]; p.Key != "team1" || p.StorageQuotaBytes != 1073741824 {
+		t.Errorf("Unexpected project: %+v", p)
+	}
+
+	members, err := client.FetchProjectMembers("team1", "users")
+	if err != nil {
+		t.Fatalf("FetchProjectMembers() error = %v", err)
+	}
+	if len(members) != 2 || len(members[0].Roles) != 2 {
+		t.Errorf("Unexpected project members: %+v", members)
+	}
+
+	repositories, err := client.FetchProjectRepositories("team1")
+	if err != nil {
+		t.Fatalf("FetchProjectRepositories() error = %v", err)
+	}
+	if len(repositories.Repositories) != 1 {
+		t.Errorf("Expected 1 project repository, got %d", len(repositories.Repositories))
+	}
+
+	if _, err := client.FetchProjectMembers("team2", "groups"); err == nil {
+		t.Error("Expected error for missing project members endpoint, but got none")
+	}
+}
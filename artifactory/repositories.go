@@ -0,0 +1,90 @@
+package artifactory
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+const repositoriesEndpoint = "repositories"
+
+// Repository represents single element of API respond from repositories endpoint
+type Repository struct {
+	Key         string `json:"key"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	PackageType string `json:"packageType"`
+}
+
+type Repositories struct {
+	Repositories []Repository
+	NodeId       string
+}
+
+// FetchRepositories makes the API call to repositories endpoint and returns []Repository.
+// repoType filters by repository class (local, remote, virtual, federated), empty returns all repositories.
+func (c *Client) FetchRepositories(repoType string) (Repositories, error) {
+	var repositories Repositories
+	endpoint := repositoriesEndpoint
+	if repoType != "" {
+		endpoint = fmt.Sprintf("%s?type=%s", repositoriesEndpoint, repoType)
+	}
+	c.logger.Debug(
+		"Fetching repositories",
+		"type", repoType,
+	)
+	resp, err := c.FetchHTTP(endpoint)
+	if err != nil {
+		return repositories, err
+	}
+	repositories.NodeId = resp.NodeId
+	if err := json.Unmarshal(resp.Body, &repositories.Repositories); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal repositories respond")
+		return repositories, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: endpoint,
+		}
+	}
+	return repositories, nil
+}
+
+// RepositoryConfig represents API respond from repositories/{repoKey} endpoint.
+// Only the fields used for metrics are decoded, descriptions and credentials are ignored.
+type RepositoryConfig struct {
+	Key                   string   `json:"key"`
+	Rclass                string   `json:"rclass"`
+	PackageType           string   `json:"packageType"`
+	URL                   string   `json:"url"`
+	XrayIndex             bool     `json:"xrayIndex"`
+	HandleReleases        bool     `json:"handleReleases"`
+	HandleSnapshots       bool     `json:"handleSnapshots"`
+	BlackedOut            bool     `json:"blackedOut"`
+	Offline               bool     `json:"offline"`
+	Repositories          []string `json:"repositories"`
+	DefaultDeploymentRepo string   `json:"defaultDeploymentRepo"`
+	NodeId                string   `json:"-"`
+}
+
+// FetchRepositoryConfig makes the API call to repositories/{repoKey} endpoint and returns RepositoryConfig
+func (c *Client) FetchRepositoryConfig(repoKey string) (RepositoryConfig, error) {
+	var repoConfig RepositoryConfig
+	endpoint := fmt.Sprintf("%s/%s", repositoriesEndpoint, url.PathEscape(repoKey))
+	c.logger.Debug(
+		"Fetching repository configuration",
+		"repo", repoKey,
+	)
+	resp, err := c.FetchHTTP(endpoint)
+	if err != nil {
+		return repoConfig, err
+	}
+	repoConfig.NodeId = resp.NodeId
+	if err := json.Unmarshal(resp.Body, &repoConfig); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal repository configuration respond")
+		return repoConfig, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: endpoint,
+		}
+	}
+	return repoConfig, nil
+}
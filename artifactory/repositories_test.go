@@ -0,0 +1,58 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchRepositories(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Artifactory-Node-Id", "test-node")
+		switch r.URL.Path {
+		case "/api/repositories":
+			w.Write([]byte(`[
+				{"key":"libs-release","type":"LOCAL","packageType":"Maven","url":"http://localhost/libs-release"},
+				{"key":"maven-central","type":"REMOTE","packageType":"Maven","url":"https://repo1.maven.org/maven2"}
+			]`))
+		case "/api/repositories/maven-central":
+			w.Write([]byte(`{
+				"key":"maven-central","rclass":"remote","packageType":"maven",
+				"url":"https://repo1.maven.org/maven2","password":"secret",
+				"xrayIndex":true,"handleReleases":true,"handleSnapshots":false,
+				"blackedOut":true,"offline":false
+			}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	repositories, err := client.FetchRepositories("")
+	if err != nil {
+		t.Fatalf("FetchRepositories() error = %v", err)
+	}
+	if len(repositories.Repositories) != 2 {
+		t.Fatalf("Expected 2 repositories, got %d", len(repositories.Repositories))
+	}
+	if repositories.NodeId != "test-node" {
+		t.Errorf("Repositories.NodeId = %s, want test-node", repositories.NodeId)
+	}
+
+	repoConfig, err := client.FetchRepositoryConfig("maven-central")
+	if err != nil {
+		t.Fatalf("FetchRepositoryConfig() error = %v", err)
+	}
+	if repoConfig.Rclass != "remote" || !repoConfig.XrayIndex || !repoConfig.BlackedOut || repoConfig.HandleSnapshots {
+		t.Errorf("Unexpected repository configuration: %+v", repoConfig)
+	}
+
+	if _, err := client.FetchRepositoryConfig("missing"); err == nil {
+		t.Error("Expected error for missing repository, but got none")
+	}
+}
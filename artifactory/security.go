@@ -2,12 +2,17 @@ package artifactory
 
 import (
 	"encoding/json"
+	"fmt"
+	"net/url"
 )
 
 const (
-	usersEndpoint        = "security/users"
-	groupsEndpoint       = "security/groups"
-	certificatesEndpoint = "system/security/certificates"
+	usersEndpoint             = "security/users"
+	lockedUsersEndpoint       = "security/lockedUsers"
+	userDetailsEndpoint       = "access/api/v2/users"
+	groupsEndpoint            = "security/groups"
+	permissionTargetsEndpoint = "v2/security/permissions"
+	certificatesEndpoint      = "system/security/certificates"
 )
 
 // User represents single element of API respond from users endpoint
@@ -39,10 +44,63 @@ func (c *Client) FetchUsers() (Users, error) {
 	return users, nil
 }
 
-// Group represents single element of API respond from groups endpoint
+// UserDetails represents API respond from Access users/{username} endpoint.
+// PasswordExpiration is only set for internal users when a password expiration policy applies.
+type UserDetails struct {
+	Name               string `json:"username"`
+	Realm              string `json:"realm"`
+	Admin              bool   `json:"admin"`
+	Status             string `json:"status"`
+	LastLoggedIn       string `json:"last_logged_in"`
+	PasswordExpiration string `json:"password_expiration_date"`
+}
+
+// FetchUserDetails makes the API call to Access users/{username} endpoint and returns UserDetails
+func (c *Client) FetchUserDetails(name string) (UserDetails, error) {
+	var details UserDetails
+	endpoint := fmt.Sprintf("%s/%s", userDetailsEndpoint, url.PathEscape(name))
+	c.logger.Debug(
+		"Fetching user details",
+		"user", name,
+	)
+	resp, err := c.FetchAccessHTTP(endpoint)
+	if err != nil {
+		return details, err
+	}
+	if err := json.Unmarshal(resp.Body, &details); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal user details respond")
+		return details, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: endpoint,
+		}
+	}
+	return details, nil
+}
+
+// FetchLockedUsers makes the API call to locked users endpoint and returns the names of the
+// users locked out after too many failed login attempts
+func (c *Client) FetchLockedUsers() ([]string, error) {
+	var lockedUsers []string
+	c.logger.Debug("Fetching locked users")
+	resp, err := c.FetchHTTP(lockedUsersEndpoint)
+	if err != nil {
+		return lockedUsers, err
+	}
+	if err := json.Unmarshal(resp.Body, &lockedUsers); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal locked users respond")
+		return lockedUsers, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: lockedUsersEndpoint,
+		}
+	}
+	return lockedUsers, nil
+}
+
+// Group represents single element of API respond from groups endpoint.
+// The realm of a group is only returned by the groups/{groupName} endpoint.
 type Group struct {
-	Name  string `json:"name"`
-	Realm string `json:"uri"`
+	Name string `json:"name"`
+	URI  string `json:"uri"`
 }
 type Groups struct {
 	Groups []Group
@@ -69,6 +127,108 @@ func (c *Client) FetchGroups() (Groups, error) {
 	return groups, nil
 }
 
+// PermissionTarget represents single element of API respond from permissions endpoint
+type PermissionTarget struct {
+	Name string `json:"name"`
+	URI  string `json:"uri"`
+}
+type PermissionTargets struct {
+	PermissionTargets []PermissionTarget
+	NodeId            string
+}
+
+// PermissionTargetSection represents the repositories, builds or release bundles
+// of a permission target and the actions granted on them to users and groups.
+type PermissionTargetSection struct {
+	Repositories []string `json:"repositories"`
+	Actions      struct {
+		Users  map[string][]string `json:"users"`
+		Groups map[string][]string `json:"groups"`
+	} `json:"actions"`
+}
+
+// PermissionTargetDetails represents API respond from permissions/{permissionTargetName} endpoint
+type PermissionTargetDetails struct {
+	Name          string                   `json:"name"`
+	Repo          *PermissionTargetSection `json:"repo"`
+	Build         *PermissionTargetSection `json:"build"`
+	ReleaseBundle *PermissionTargetSection `json:"releaseBundle"`
+}
+
+// FetchPermissionTargets makes the API call to permissions endpoint and returns []PermissionTarget
+func (c *Client) FetchPermissionTargets() (PermissionTargets, error) {
+	var targets PermissionTargets
+	c.logger.Debug("Fetching permission targets")
+	resp, err := c.FetchHTTP(permissionTargetsEndpoint)
+	if err != nil {
+		return targets, err
+	}
+	targets.NodeId = resp.NodeId
+	if err := json.Unmarshal(resp.Body, &targets.PermissionTargets); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal permission targets respond")
+		return targets, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: permissionTargetsEndpoint,
+		}
+	}
+	return targets, nil
+}
+
+// FetchPermissionTarget makes the API call to permissions/{permissionTargetName} endpoint
+// and returns the repositories and actions of the permission target.
+func (c *Client) FetchPermissionTarget(name string) (PermissionTargetDetails, error) {
+	var details PermissionTargetDetails
+	endpoint := fmt.Sprintf("%s/%s", permissionTargetsEndpoint, url.PathEscape(name))
+	c.logger.Debug(
+		"Fetching permission target",
+		"name", name,
+	)
+	resp, err := c.FetchHTTP(endpoint)
+	if err != nil {
+		return details, err
+	}
+	if err := json.Unmarshal(resp.Body, &details); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal permission target respond")
+		return details, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: endpoint,
+		}
+	}
+	return details, nil
+}
+
+// GroupDetails represents API respond from groups/{groupName} endpoint
+type GroupDetails struct {
+	Name            string   `json:"name"`
+	Realm           string   `json:"realm"`
+	AutoJoin        bool     `json:"autoJoin"`
+	AdminPrivileges bool     `json:"adminPrivileges"`
+	UserNames       []string `json:"userNames"`
+}
+
+// FetchGroupDetails makes the API call to groups/{groupName} endpoint and returns
+// GroupDetails, including the names of the members of the group
+func (c *Client) FetchGroupDetails(name string) (GroupDetails, error) {
+	var details GroupDetails
+	endpoint := fmt.Sprintf("%s/%s?includeUsers=true", groupsEndpoint, url.PathEscape(name))
+	c.logger.Debug(
+		"Fetching group details",
+		"group", name,
+	)
+	resp, err := c.FetchHTTP(endpoint)
+	if err != nil {
+		return details, err
+	}
+	if err := json.Unmarshal(resp.Body, &details); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal group details respond")
+		return details, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: endpoint,
+		}
+	}
+	return details, nil
+}
+
 // Certificate represents a single element of an API response from the certificates endpoint
 type Certificate struct {
 	CertificateAlias string `json:"certificateAlias"`
@@ -1,11 +1,19 @@
 package artifactory
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
 )
 
 const (
-	storageInfoEndpoint = "storageinfo"
+	storageInfoEndpoint          = "storageinfo"
+	storageInfoCalculateEndpoint = "storageinfo/calculate"
 )
 
 // StorageInfo represents API respond from license storageinfo
@@ -18,13 +26,7 @@ type StorageInfo struct {
 		ItemsCount     string `json:"itemsCount"`
 		ArtifactsCount string `json:"artifactsCount"`
 	} `json:"binariesSummary"`
-	FileStoreSummary struct {
-		StorageType      string `json:"storageType"`
-		StorageDirectory string `json:"storageDirectory"`
-		TotalSpace       string `json:"totalSpace"`
-		UsedSpace        string `json:"usedSpace"`
-		FreeSpace        string `json:"freeSpace"`
-	} `json:"fileStoreSummary"`
+	FileStoreSummaries      FileStoreSummaries `json:"fileStoreSummary"`
 	RepositoriesSummaryList []struct {
 		RepoKey      string `json:"repoKey"`
 		RepoType     string `json:"repoType"`
@@ -38,6 +40,43 @@ type StorageInfo struct {
 	NodeId string
 }
 
+// FileStoreSummary represents a single binary store of the storageinfo respond
+type FileStoreSummary struct {
+	StorageType      string `json:"storageType"`
+	StorageDirectory string `json:"storageDirectory"`
+	TotalSpace       string `json:"totalSpace"`
+	UsedSpace        string `json:"usedSpace"`
+	FreeSpace        string `json:"freeSpace"`
+}
+
+// FileStoreSummaries holds the binary stores of the storageinfo respond.
+// `fileStoreSummary` is a single object for a plain filestore and a list
+// when several stores are reported, e.g. sharded, S3 with a cache fs or
+// cluster filestores.
+type FileStoreSummaries []FileStoreSummary
+
+func (f *FileStoreSummaries) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.Equal(trimmed, []byte("null")) {
+		*f = nil
+		return nil
+	}
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var stores []FileStoreSummary
+		if err := json.Unmarshal(trimmed, &stores); err != nil {
+			return err
+		}
+		*f = stores
+		return nil
+	}
+	var store FileStoreSummary
+	if err := json.Unmarshal(trimmed, &store); err != nil {
+		return err
+	}
+	*f = FileStoreSummaries{store}
+	return nil
+}
+
 // FetchStorageInfo makes the API call to storageinfo endpoint and returns StorageInfo
 func (c *Client) FetchStorageInfo() (StorageInfo, error) {
 	var storageInfo StorageInfo
@@ -47,6 +86,7 @@ func (c *Client) FetchStorageInfo() (StorageInfo, error) {
 		return storageInfo, err
 	}
 	storageInfo.NodeId = resp.NodeId
+	c.storageInfoTracker.observe(resp.Body)
 	if err := json.Unmarshal(resp.Body, &storageInfo); err != nil {
 		c.logger.Error("There was an issue when try to unmarshal storageInfo respond")
 		return storageInfo, &UnmarshalError{
@@ -56,3 +96,125 @@ func (c *Client) FetchStorageInfo() (StorageInfo, error) {
 	}
 	return storageInfo, nil
 }
+
+// StorageInfoFreshness holds when the exporter last requested a storage summary
+// calculation and when its result was first observed. Zero times mean never.
+type StorageInfoFreshness struct {
+	Requested time.Time
+	Completed time.Time
+}
+
+// storageInfoTracker detects the completion of asynchronous storage summary
+// calculations. Artifactory does not report when a calculation finished, so
+// the first storageinfo response differing from the one served when the
+// calculation was requested marks it as completed. A calculation yielding the
+// same summary, e.g. because nothing changed in between, cannot be told apart
+// from a running one: it is considered completed by the last response
+// observed before the next calculation is requested.
+type storageInfoTracker struct {
+	mutex         sync.Mutex
+	lastHash      [sha256.Size]byte
+	seen          bool
+	lastObserved  time.Time
+	hashAtRequest [sha256.Size]byte
+	baselineSet   bool
+	pending       bool
+	freshness     StorageInfoFreshness
+}
+
+func (t *storageInfoTracker) observe(body []byte) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	hash := sha256.Sum256(body)
+	t.lastHash = hash
+	t.seen = true
+	t.lastObserved = time.Now()
+	if !t.pending {
+		return
+	}
+	if !t.baselineSet {
+		// No storageinfo was seen before the request, use this one as the baseline.
+		t.hashAtRequest = hash
+		t.baselineSet = true
+		return
+	}
+	if hash != t.hashAtRequest {
+		t.freshness.Completed = t.lastObserved
+		t.pending = false
+	}
+}
+
+func (t *storageInfoTracker) requested() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.pending && t.lastObserved.After(t.freshness.Requested) {
+		// The previous calculation had a whole interval to finish without
+		// changing the summary.
+		t.freshness.Completed = t.lastObserved
+	}
+	t.freshness.Requested = time.Now()
+	t.pending = true
+	t.hashAtRequest = t.lastHash
+	t.baselineSet = t.seen
+}
+
+func (t *storageInfoTracker) get() StorageInfoFreshness {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.freshness
+}
+
+// RequestStorageInfoCalculation asks Artifactory to recalculate the storage summary.
+// The calculation runs asynchronously, its completion is detected by FetchStorageInfo.
+func (c *Client) RequestStorageInfoCalculation() error {
+	fullPath := fmt.Sprintf("%s/api/%s", c.URI, storageInfoCalculateEndpoint)
+	c.logger.Debug(
+		"Requesting storage info calculation",
+		"path", fullPath,
+	)
+	// Not cached: falling back to a cached answer would hide a failed request.
+	resp, err := c.makeRequest(c.ctx, http.MethodPost, fullPath, nil, nil)
+	if err != nil {
+		c.logger.Error(
+			logMsgErrAPICall,
+			"endpoint", fullPath,
+			"err", err.Error(),
+		)
+		return err
+	}
+	defer resp.Body.Close()
+	if _, err := c.handleResponse(resp, fullPath); err != nil {
+		return err
+	}
+	c.storageInfoTracker.requested()
+	return nil
+}
+
+// StorageInfoFreshness returns when the storage summary calculation was last requested and completed.
+func (c *Client) StorageInfoFreshness() StorageInfoFreshness {
+	return c.storageInfoTracker.get()
+}
+
+// refreshStorageInfo requests a storage summary calculation right away and
+// then on every interval until ctx is cancelled.
+func (c *Client) refreshStorageInfo(ctx context.Context, interval time.Duration) {
+	defer c.background.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.RequestStorageInfoCalculation(); err != nil {
+			c.logger.Warn(
+				"Couldn't request storage info calculation",
+				"err", err.Error(),
+			)
+		}
+		select {
+		case <-ctx.Done():
+			c.logger.Debug("Stopping storage info refresh")
+			return
+		case <-ticker.C:
+		}
+	}
+}
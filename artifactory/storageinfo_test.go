@@ -0,0 +1,141 @@
+package artifactory
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStorageInfoCalculation(t *testing.T) {
+	var calculated atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/storageinfo/calculate":
+			if r.Method != http.MethodPost {
+				t.Errorf("Expected POST request, got %s", r.Method)
+			}
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"info":"Calculation of storage info was scheduled"}`))
+		case "/api/storageinfo":
+			if calculated.Load() {
+				w.Write([]byte(`{"binariesSummary":{"itemsCount":"2"}}`))
+				return
+			}
+			w.Write([]byte(`{"binariesSummary":{"itemsCount":"1"}}`))
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	if _, err := client.FetchStorageInfo(); err != nil {
+		t.Fatalf("FetchStorageInfo() error = %v", err)
+	}
+	if err := client.RequestStorageInfoCalculation(); err != nil {
+		t.Fatalf("RequestStorageInfoCalculation() error = %v", err)
+	}
+	freshness := client.StorageInfoFreshness()
+	if freshness.Requested.IsZero() {
+		t.Error("Expected Requested to be set")
+	}
+
+	// The calculation did not finish yet, the summary is unchanged.
+	if _, err := client.FetchStorageInfo(); err != nil {
+		t.Fatalf("FetchStorageInfo() error = %v", err)
+	}
+	if !client.StorageInfoFreshness().Completed.IsZero() {
+		t.Error("Expected Completed to be unset while the summary is unchanged")
+	}
+
+	calculated.Store(true)
+	if _, err := client.FetchStorageInfo(); err != nil {
+		t.Fatalf("FetchStorageInfo() error = %v", err)
+	}
+	freshness = client.StorageInfoFreshness()
+	if freshness.Completed.IsZero() {
+		t.Fatal("Expected Completed to be set once the summary changed")
+	}
+	if freshness.Completed.Before(freshness.Requested) {
+		t.Error("Expected Completed to be after Requested")
+	}
+}
+
+func TestStorageInfoCalculationUnchangedSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/storageinfo/calculate":
+			w.WriteHeader(http.StatusAccepted)
+		case "/api/storageinfo":
+			w.Write([]byte(`{"binariesSummary":{"itemsCount":"1"}}`))
+		}
+	}))
+	defer server.Close()
+
+	conf := createTestConfig()
+	conf.ArtiScrapeURI = server.URL
+	client := NewClient(conf)
+
+	if _, err := client.FetchStorageInfo(); err != nil {
+		t.Fatalf("FetchStorageInfo() error = %v", err)
+	}
+	if err := client.RequestStorageInfoCalculation(); err != nil {
+		t.Fatalf("RequestStorageInfoCalculation() error = %v", err)
+	}
+	if _, err := client.FetchStorageInfo(); err != nil {
+		t.Fatalf("FetchStorageInfo() error = %v", err)
+	}
+	if !client.StorageInfoFreshness().Completed.IsZero() {
+		t.Error("Expected Completed to be unset before the next calculation is requested")
+	}
+
+	// The summary never changed, the previous calculation completed by the
+	// time the next one is requested.
+	firstRequested := client.StorageInfoFreshness().Requested
+	if err := client.RequestStorageInfoCalculation(); err != nil {
+		t.Fatalf("RequestStorageInfoCalculation() error = %v", err)
+	}
+	freshness := client.StorageInfoFreshness()
+	if freshness.Completed.IsZero() {
+		t.Fatal("Expected Completed to be set once the next calculation is requested")
+	}
+	if freshness.Completed.Before(firstRequested) || freshness.Completed.After(freshness.Requested) {
+		t.Errorf("Expected Completed %v between the two requests %v and %v", freshness.Completed, firstRequested, freshness.Requested)
+	}
+
+	// Without any storageinfo response since, the calculation is not assumed completed.
+	completed := freshness.Completed
+	if err := client.RequestStorageInfoCalculation(); err != nil {
+		t.Fatalf("RequestStorageInfoCalculation() error = %v", err)
+	}
+	if got := client.StorageInfoFreshness().Completed; !got.Equal(completed) {
+		t.Errorf("Completed = %v, want %v", got, completed)
+	}
+}
+
+func TestFileStoreSummariesUnmarshal(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{"object", `{"fileStoreSummary":{"storageType":"file-system","storageDirectory":"/data","usedSpace":"1 GB (10%)"}}`, 1},
+		{"array", `{"fileStoreSummary":[{"storageType":"file-system","storageDirectory":"/data"},{"storageType":"s3","storageDirectory":"bucket"}]}`, 2},
+		{"null", `{"fileStoreSummary":null}`, 0},
+		{"missing", `{}`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info StorageInfo
+			if err := json.Unmarshal([]byte(tt.data), &info); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got := len(info.FileStoreSummaries); got != tt.want {
+				t.Errorf("len(FileStoreSummaries) = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package artifactory
+
+import (
+	"encoding/json"
+)
+
+const accessTokensEndpoint = "access/api/v1/tokens"
+
+// AccessToken represents single element of API respond from Access tokens endpoint.
+// Expiry is a Unix timestamp in seconds, 0 for tokens which never expire. The
+// list does not return the scope of the tokens.
+type AccessToken struct {
+	TokenId     string `json:"token_id"`
+	Subject     string `json:"subject"`
+	Expiry      int64  `json:"expiry"`
+	IssuedAt    int64  `json:"issued_at"`
+	Refreshable bool   `json:"refreshable"`
+}
+
+type AccessTokens struct {
+	Tokens []AccessToken `json:"tokens"`
+	NodeId string
+}
+
+// FetchAccessTokens makes the API call to Access tokens endpoint and returns []AccessToken
+func (c *Client) FetchAccessTokens() (AccessTokens, error) {
+	var tokens AccessTokens
+	c.logger.Debug("Fetching access tokens")
+
+	// Use ping endpoint to retrieve nodeID, since this is not returned by access API
+	resp, err := c.FetchHTTP(pingEndpoint)
+	if err != nil {
+		return tokens, err
+	}
+	tokens.NodeId = resp.NodeId
+
+	resp, err = c.FetchAccessHTTP(accessTokensEndpoint)
+	if err != nil {
+		return tokens, err
+	}
+	if err := json.Unmarshal(resp.Body, &tokens); err != nil {
+		c.logger.Error("There was an issue when try to unmarshal access tokens respond")
+		return tokens, &UnmarshalError{
+			message:  err.Error(),
+			endpoint: accessTokensEndpoint,
+		}
+	}
+	return tokens, nil
+}
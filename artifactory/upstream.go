@@ -0,0 +1,57 @@
+package artifactory
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// UpstreamProbe is the result of a reachability check of a remote repository URL.
+type UpstreamProbe struct {
+	Reachable  bool
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+func newUpstreamClient(sslVerify bool, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: !sslVerify},
+		},
+		// A redirect already proves the upstream answers.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// ProbeUpstream sends an unauthenticated HEAD request to the URL of a remote repository.
+// Artifactory credentials are never sent upstream. Any response below 500 counts as
+// reachable, since upstreams such as Docker Hub reject anonymous requests to their root.
+func (c *Client) ProbeUpstream(url string) UpstreamProbe {
+	var probe UpstreamProbe
+	req, err := http.NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		probe.Err = err
+		return probe
+	}
+	start := time.Now()
+	resp, err := c.upstreamClient.Do(req)
+	probe.Duration = time.Since(start)
+	if err != nil {
+		c.logger.Debug(
+			"Upstream probe failed",
+			"url", url,
+			"err", err.Error(),
+		)
+		probe.Err = err
+		return probe
+	}
+	resp.Body.Close()
+	probe.StatusCode = resp.StatusCode
+	probe.Reachable = resp.StatusCode < http.StatusInternalServerError
+	return probe
+}
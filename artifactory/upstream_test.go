@@ -0,0 +1,85 @@
+package artifactory
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProbeUpstream(t *testing.T) {
+	tests := []struct {
+		name            string
+		statusCode      int
+		expectReachable bool
+	}{
+		{"OK", http.StatusOK, true},
+		{"Anonymous access rejected", http.StatusUnauthorized, true},
+		{"Upstream error", http.StatusServiceUnavailable, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodHead {
+					t.Errorf("Expected HEAD request, got %s", r.Method)
+				}
+				if r.Header.Get("Authorization") != "" {
+					t.Error("Artifactory credentials must not be sent upstream")
+				}
+				w.WriteHeader(tt.statusCode)
+			}))
+			defer server.Close()
+
+			conf := createTestConfig()
+			conf.RemoteRepoURLCheckTimeout = time.Second
+			client := NewClient(conf)
+
+			probe := client.ProbeUpstream(server.URL)
+			if probe.Reachable != tt.expectReachable {
+				t.Errorf("ProbeUpstream().Reachable = %v, want %v", probe.Reachable, tt.expectReachable)
+			}
+			if probe.StatusCode != tt.statusCode {
+				t.Errorf("ProbeUpstream().StatusCode = %d, want %d", probe.StatusCode, tt.statusCode)
+			}
+		})
+	}
+
+	t.Run("Connection refused", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		url := server.URL
+		server.Close()
+
+		conf := createTestConfig()
+		conf.RemoteRepoURLCheckTimeout = time.Second
+		client := NewClient(conf)
+
+		probe := client.ProbeUpstream(url)
+		if probe.Reachable {
+			t.Error("Expected closed upstream to be unreachable")
+		}
+		if probe.Err == nil {
+			t.Error("Expected probe error for closed upstream")
+		}
+	})
+
+	t.Run("Upstream TLS verified independently of Artifactory", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		for _, sslVerify := range []bool{true, false} {
+			conf := createTestConfig()
+			conf.ArtiSSLVerify = false
+			conf.RemoteRepoURLSSLVerify = sslVerify
+			conf.RemoteRepoURLCheckTimeout = time.Second
+			client := NewClient(conf)
+
+			probe := client.ProbeUpstream(server.URL)
+			if probe.Reachable == sslVerify {
+				t.Errorf("ProbeUpstream() of a self-signed upstream with ssl verify %v: Reachable = %v", sslVerify, probe.Reachable)
+			}
+		}
+	})
+}
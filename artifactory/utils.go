@@ -2,8 +2,10 @@ package artifactory
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"slices"
@@ -23,8 +25,10 @@ type APIErrors struct {
 }
 
 type ApiResponse struct {
-	Body   []byte
-	NodeId string
+	Body         []byte
+	NodeId       string
+	ETag         string
+	LastModified string
 }
 
 var (
@@ -42,13 +46,21 @@ var (
 	}
 )
 
-func (c *Client) makeRequest(method string, path string, body []byte, headers **map[string]string) (*http.Response, error) {
-	req, err := http.NewRequest(method, path, bytes.NewBuffer(body))
+// makeRequest sends a request which is aborted once ctx is done or the client is closed.
+func (c *Client) makeRequest(ctx context.Context, method string, path string, body []byte, headers **map[string]string) (*http.Response, error) {
+	return c.makeRequestWith(ctx, c.client, method, path, body, headers)
+}
+
+// makeRequestWith is makeRequest sending the request with the given HTTP client.
+func (c *Client) makeRequestWith(ctx context.Context, client *http.Client, method string, path string, body []byte, headers **map[string]string) (*http.Response, error) {
+	ctx, stop := mergeCancel(ctx, c.ctx)
+	req, err := http.NewRequestWithContext(ctx, method, path, bytes.NewBuffer(body))
 	if err != nil {
 		c.logger.Error(
 			"There was an error creating request",
 			"err", err.Error(),
 		)
+		stop()
 		return nil, err
 	}
 	switch c.authMethod {
@@ -57,6 +69,7 @@ func (c *Client) makeRequest(method string, path string, body []byte, headers **
 	case "accessToken":
 		req.Header.Add("Authorization", "Bearer "+c.cred.AccessToken)
 	default:
+		stop()
 		return nil, fmt.Errorf("Artifactory Auth (%s) method is not supported", c.authMethod)
 	}
 	if headers != nil {
@@ -64,7 +77,36 @@ func (c *Client) makeRequest(method string, path string, body []byte, headers **
 			req.Header.Set(key, value)
 		}
 	}
-	return c.client.Do(req)
+	resp, err := client.Do(req)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: stop}
+	return resp, nil
+}
+
+// mergeCancel returns a copy of ctx which is also cancelled when other is
+// done. The returned function releases the resources of the copy.
+func mergeCancel(ctx context.Context, other context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(other, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// cancelOnClose releases the context of a response when its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
 }
 
 func (c *Client) handleResponse(resp *http.Response, fullPath string) (*ApiResponse, error) {
@@ -115,12 +157,33 @@ func (c *Client) handleResponse(resp *http.Response, fullPath string) (*ApiRespo
 	}
 
 	response := &ApiResponse{
-		Body:   bodyBytes,
-		NodeId: resp.Header.Get("x-artifactory-node-id"),
+		Body:         bodyBytes,
+		NodeId:       resp.Header.Get("x-artifactory-node-id"),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
 	}
 	return response, nil
 }
 
+// withConditionalHeaders adds If-None-Match/If-Modified-Since headers built
+// from the validators of a previously cached response.
+func withConditionalHeaders(headers **map[string]string, validators *ApiResponse) **map[string]string {
+	merged := map[string]string{}
+	if headers != nil && *headers != nil {
+		for key, value := range **headers {
+			merged[key] = value
+		}
+	}
+	if validators.ETag != "" {
+		merged["If-None-Match"] = validators.ETag
+	}
+	if validators.LastModified != "" {
+		merged["If-Modified-Since"] = validators.LastModified
+	}
+	mergedPtr := &merged
+	return &mergedPtr
+}
+
 func (c *Client) makeCachedRequest(method string, path string, body []byte, headers **map[string]string) (*ApiResponse, error) {
 	key := fmt.Sprintf("%s_%s_%s", method, path, body)
 	cached := NewCached(key, c.responseCache, c.logger)
@@ -133,9 +196,18 @@ func (c *Client) makeCachedRequest(method string, path string, body []byte, head
 		cached.Close()
 	}()
 
+	// Only GET requests are revalidated, AQL queries are POSTed and never carry validators.
+	var validators *ApiResponse
+	if method == http.MethodGet {
+		if v, exists := cached.GetValidators(); exists {
+			validators = v
+			headers = withConditionalHeaders(headers, validators)
+		}
+	}
+
 	go func() {
 		defer wg.Done()
-		resp, err := c.makeRequest(method, path, body, headers)
+		resp, err := c.makeRequest(c.ctx, method, path, body, headers)
 		if err != nil {
 			c.logger.Error(
 				logMsgErrAPICall,
@@ -146,11 +218,22 @@ func (c *Client) makeCachedRequest(method string, path string, body []byte, head
 			return
 		}
 		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotModified && validators != nil {
+			c.logger.Debug(
+				"Resource not modified, using cached response",
+				"path", path,
+			)
+			cached.Revalidated(validators)
+			c.lastPayloads.record(method, path, validators)
+			cached.responses <- validators
+			return
+		}
 		apiResp, err := c.handleResponse(resp, path)
 		if err != nil {
 			cached.errors <- err
 			return
 		}
+		c.lastPayloads.record(method, path, apiResp)
 		cached.responses <- apiResp
 		cached.CacheResponse(apiResp)
 	}()
@@ -174,6 +257,14 @@ func (c *Client) makeCachedRequest(method string, path string, body []byte, head
 	}
 }
 
+// CacheStats returns the statistics of the response cache.
+func (c *Client) CacheStats() CacheStats {
+	if c.responseCache == nil {
+		return CacheStats{}
+	}
+	return c.responseCache.Stats()
+}
+
 // FetchHTTP is a wrapper function for making all Get API calls
 func (c *Client) FetchHTTP(path string) (*ApiResponse, error) {
 	fullPath := fmt.Sprintf("%s/api/%s", c.URI, path)
@@ -184,6 +275,18 @@ func (c *Client) FetchHTTP(path string) (*ApiResponse, error) {
 	return c.makeCachedRequest("GET", fullPath, nil, nil)
 }
 
+// FetchAccessHTTP is a wrapper function for making Get API calls to JFrog Access
+// Note: path is relative to the platform URL (e.g. "access/api/v1/projects")
+func (c *Client) FetchAccessHTTP(path string) (*ApiResponse, error) {
+	artifactoryURI := strings.TrimSuffix(c.URI, "/artifactory")
+	fullPath := fmt.Sprintf("%s/%s", artifactoryURI, path)
+	c.logger.Debug(
+		"Fetching http",
+		"path", fullPath,
+	)
+	return c.makeCachedRequest("GET", fullPath, nil, nil)
+}
+
 // QueryAQL is a wrapper function for making an query to AQL endpoint
 func (c *Client) QueryAQL(query []byte) (*ApiResponse, error) {
 	fullPath := fmt.Sprintf("%s/api/search/aql", c.URI)
@@ -194,6 +297,48 @@ func (c *Client) QueryAQL(query []byte) (*ApiResponse, error) {
 	return c.makeCachedRequest("POST", fullPath, query, nil)
 }
 
+// AQLStream is the body of a streamed AQL response.
+type AQLStream struct {
+	io.ReadCloser
+	NodeId string
+}
+
+// StreamAQL runs an AQL query and returns the response body to be decoded
+// while it is read, so large results are never held in memory at once. The
+// response is neither cached nor recorded for debugging. The query is
+// aborted once ctx is done or after the AQL timeout, which unlike the
+// timeout of other requests includes reading the body. The caller must close
+// the body.
+func (c *Client) StreamAQL(ctx context.Context, query []byte) (*AQLStream, error) {
+	fullPath := fmt.Sprintf("%s/api/search/aql", c.URI)
+	c.logger.Debug(
+		"Streaming AQL query",
+		"path", fullPath,
+	)
+	ctx, cancel := context.WithTimeout(ctx, c.aqlTimeout)
+	resp, err := c.makeRequestWith(ctx, c.aqlClient, http.MethodPost, fullPath, query, nil)
+	if err != nil {
+		cancel()
+		c.logger.Error(
+			logMsgErrAPICall,
+			"endpoint", fullPath,
+			"err", err.Error(),
+		)
+		return nil, err
+	}
+	if !slices.Contains(httpSuccCodes, resp.StatusCode) {
+		defer cancel()
+		defer resp.Body.Close()
+		// handleResponse turns any error status into an error
+		_, err := c.handleResponse(resp, fullPath)
+		return nil, err
+	}
+	return &AQLStream{
+		ReadCloser: &cancelOnClose{ReadCloser: resp.Body, cancel: cancel},
+		NodeId:     resp.Header.Get("x-artifactory-node-id"),
+	}, nil
+}
+
 // PostHTTP is a wrapper function for making all Post API calls
 // Note: the API endpoint (e.g. "/artifactory" or "/access") needs to be part of path
 func (c *Client) PostHTTP(path string, body []byte, headers *map[string]string) (*ApiResponse, error) {
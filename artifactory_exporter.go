@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/common/version"
+	"github.com/prometheus/exporter-toolkit/web"
 
 	"github.com/peimanja/artifactory_exporter/collector"
 	"github.com/peimanja/artifactory_exporter/config"
@@ -32,6 +38,7 @@ func main() {
 		)
 		os.Exit(1)
 	}
+	defer exporter.Close()
 	collector.InitMetrics(exporter)
 	prometheus.MustRegister(exporter)
 	conf.Logger.Info(
@@ -42,10 +49,6 @@ func main() {
 		"Build context",
 		"context", version.BuildContext(),
 	)
-	conf.Logger.Info(
-		"Listening on address",
-		"address", conf.ListenAddress,
-	)
 	http.HandleFunc(conf.MetricsPath, func(w http.ResponseWriter, r *http.Request) {
 		conf.Logger.Debug(
 			"Prometheus scrape",
@@ -54,28 +57,94 @@ func main() {
 		)
 		promhttp.Handler().ServeHTTP(w, r)
 	})
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		w.Write([]byte(`<html>
-             <head><title>JFrog Artifactory Exporter</title></head>
-             <body>
-             <h1>JFrog Exporter</h1>
-             <p><a href='` + conf.MetricsPath + `'>Metrics</a></p>
-             </body>
-             </html>`))
-	})
+	http.HandleFunc("/", landingPageHandler(conf, exporter))
 	http.HandleFunc("/-/healthy", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		fmt.Fprintf(w, "OK")
 	})
 	http.HandleFunc("/-/ready", func(w http.ResponseWriter, r *http.Request) {
+		if !exporter.CheckReady() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "Artifactory is not reachable or credentials are invalid")
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 		fmt.Fprintf(w, "OK")
 	})
-	if err := http.ListenAndServe(conf.ListenAddress, nil); err != nil {
-		conf.Logger.Error(
-			"Error starting HTTP server",
-			"err", err.Error(),
+	http.HandleFunc("/-/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(exporter.Status()); err != nil {
+			conf.Logger.Error(
+				"Error encoding exporter status",
+				"err", err.Error(),
+			)
+		}
+	})
+
+	if conf.DebugLastScrape {
+		http.HandleFunc("/debug/last-scrape", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(exporter.LastPayloads()); err != nil {
+				conf.Logger.Error(
+					"Error encoding last scrape payloads",
+					"err", err.Error(),
+				)
+			}
+		})
+	}
+
+	if conf.ExporterRuntimeConfig.OptionalMetrics.TopArtifacts {
+		http.HandleFunc("/top-artifacts", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(exporter.TopArtifacts()); err != nil {
+				conf.Logger.Error(
+					"Error encoding top artifacts report",
+					"err", err.Error(),
+				)
+			}
+		})
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// TLS, basic auth and security headers from the web config file wrap
+	// every handler registered above.
+	server := &http.Server{}
+	systemdSocket := false
+	webFlags := &web.FlagConfig{
+		WebListenAddresses: &[]string{conf.ListenAddress},
+		WebSystemdSocket:   &systemdSocket,
+		WebConfigFile:      &conf.WebConfigFile,
+	}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- web.ListenAndServe(server, webFlags, conf.Logger)
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			conf.Logger.Error(
+				"Error starting HTTP server",
+				"err", err.Error(),
+			)
+			exporter.Close()
+			os.Exit(1)
+		}
+	case <-ctx.Done():
+		conf.Logger.Info(
+			"Shutting down, waiting for in-flight scrapes",
+			"timeout", conf.ShutdownTimeout,
 		)
-		os.Exit(1)
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), conf.ShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			conf.Logger.Error(
+				"Error shutting down HTTP server",
+				"err", err.Error(),
+			)
+		}
 	}
+	conf.Logger.Info("artifactory_exporter stopped")
 }
@@ -0,0 +1,148 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+// maxAQLQuerySeries bounds the number of series exported by each user-defined
+// AQL query. Results of further label values are skipped.
+const maxAQLQuerySeries = 10000
+
+// newAQLQueryCollector returns a scheduled collector running a user-defined AQL
+// query and exporting its results as the gauge artifactory_aql_<name>.
+func (e *Exporter) newAQLQueryCollector(query config.AQLQuery) *scheduledCollector {
+	specs := query.LabelSpecs()
+	labelNames := make([]string, 0, len(specs)+len(defaultLabelNames))
+	for _, spec := range specs {
+		labelNames = append(labelNames, spec.Name)
+	}
+	desc := newMetric(query.Name, "aql", query.Help, append(labelNames, defaultLabelNames...))
+	return &scheduledCollector{
+		name:     "aql_" + query.Name,
+		interval: query.Interval,
+		descs:    []*prometheus.Desc{desc},
+		collect: func(ctx context.Context, ch chan<- prometheus.Metric) error {
+			return e.exportAQLQuery(ctx, query, specs, desc, ch)
+		},
+	}
+}
+
+// exportAQLQuery runs a user-defined AQL query and exports the number of
+// results, or the sum of a field, by label values. The response is decoded
+// while it is read and is never cached.
+func (e *Exporter) exportAQLQuery(ctx context.Context, query config.AQLQuery, specs []config.AQLLabel, desc *prometheus.Desc, ch chan<- prometheus.Metric) error {
+	e.logger.Debug(
+		"Running user-defined AQL query",
+		"name", query.Name,
+	)
+
+	// map[<joined label values>] <value>
+	values := map[string]float64{}
+	groups := map[string][]string{}
+	skipped := 0
+	_, nodeId, err := e.streamAQL(ctx, query.Query, func(dec *json.Decoder) error {
+		var item map[string]interface{}
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		labelValues := make([]string, len(specs))
+		for i, spec := range specs {
+			if spec.Property != "" {
+				labelValues[i] = aqlPropertyValue(item, spec.Property)
+			} else {
+				labelValues[i] = aqlFieldString(item[spec.Field])
+			}
+		}
+		value := 1.0
+		if query.Value == config.AQLValueSum {
+			var err error
+			value, err = aqlFieldNumber(item[query.Field])
+			if err != nil {
+				e.logger.Debug(
+					"Skipping AQL result without a numeric field",
+					"name", query.Name,
+					"field", query.Field,
+					"err", err.Error(),
+				)
+				e.jsonParseFailures.Inc()
+				return nil
+			}
+		}
+		key := strings.Join(labelValues, "\xff")
+		if _, exists := values[key]; !exists && len(values) >= maxAQLQuerySeries {
+			skipped++
+			return nil
+		}
+		values[key] += value
+		groups[key] = labelValues
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if skipped > 0 {
+		e.logger.Warn(
+			"Too many series for user-defined AQL query, skipping the results of the remaining label values",
+			"name", query.Name,
+			"limit", maxAQLQuerySeries,
+			"skipped_results", skipped,
+		)
+	}
+
+	for key, value := range values {
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", query.Name,
+			"labels", groups[key],
+			"value", value,
+		)
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, append(groups[key], nodeId)...)
+	}
+	return nil
+}
+
+// aqlPropertyValue returns the values of a property of an AQL result
+// included with .include("property"), joined with commas.
+func aqlPropertyValue(item map[string]interface{}, key string) string {
+	properties, _ := item["properties"].([]interface{})
+	var values []string
+	for _, p := range properties {
+		property, _ := p.(map[string]interface{})
+		if property["key"] == key {
+			values = append(values, aqlFieldString(property["value"]))
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+func aqlFieldString(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+func aqlFieldNumber(v interface{}) (float64, error) {
+	switch value := v.(type) {
+	case float64:
+		return value, nil
+	case string:
+		return strconv.ParseFloat(value, 64)
+	default:
+		return 0, fmt.Errorf("not a number: %v", v)
+	}
+}
@@ -0,0 +1,109 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+func TestExportAQLQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[
+			{"repo":"docker-local","name":"manifest.json","size":100,"properties":[{"key":"team","value":"a"}]},
+			{"repo":"docker-local","name":"manifest.json","size":50,"properties":[{"key":"team","value":"a"}]},
+			{"repo":"docker-local","name":"manifest.json","size":25},
+			{"repo":"helm-local","name":"chart.tgz","size":"10","properties":[{"key":"team","value":"b"}]}
+		]}`))
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+
+	query := config.AQLQuery{
+		Name:   "size_by_team",
+		Help:   "Size by team.",
+		Query:  `items.find().include("repo","size","property")`,
+		Value:  config.AQLValueSum,
+		Field:  "size",
+		Labels: []string{"repo", "@team"},
+	}
+	collector := e.newAQLQueryCollector(query)
+	ch := make(chan prometheus.Metric, 10)
+	if err := collector.collect(context.Background(), ch); err != nil {
+		t.Fatalf("collect() error = %v", err)
+	}
+	close(ch)
+
+	want := map[[2]string]float64{
+		{"docker-local", "a"}: 150,
+		{"docker-local", ""}:  25,
+		{"helm-local", "b"}:   10,
+	}
+	got := map[[2]string]float64{}
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		labels := map[string]string{}
+		for _, l := range metric.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		got[[2]string{labels["repo"], labels["team"]}] = metric.GetGauge().GetValue()
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Got %d series, want %d: %v", len(got), len(want), got)
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("Value for %v = %v, want %v", key, got[key], value)
+		}
+	}
+}
+
+func TestExportAQLQuerySeriesLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"results":[`))
+		for i := 0; i < maxAQLQuerySeries+5; i++ {
+			if i > 0 {
+				w.Write([]byte(`,`))
+			}
+			fmt.Fprintf(w, `{"repo":"repo-%d"}`, i)
+		}
+		// Results of label values already exported are still counted
+		w.Write([]byte(`,{"repo":"repo-0"}]}`))
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+
+	collector := e.newAQLQueryCollector(config.AQLQuery{
+		Name:   "items",
+		Help:   "Items.",
+		Query:  `items.find()`,
+		Value:  config.AQLValueCount,
+		Labels: []string{"repo"},
+	})
+	ch := make(chan prometheus.Metric, maxAQLQuerySeries+10)
+	if err := collector.collect(context.Background(), ch); err != nil {
+		t.Fatalf("collect() error = %v", err)
+	}
+	close(ch)
+	if len(ch) != maxAQLQuerySeries {
+		t.Fatalf("Got %d series, want %d", len(ch), maxAQLQuerySeries)
+	}
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if labelValue(&metric, "repo") == "repo-0" && metric.GetGauge().GetValue() != 2 {
+			t.Errorf("Value for repo-0 = %v, want 2", metric.GetGauge().GetValue())
+		}
+	}
+}
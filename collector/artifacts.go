@@ -1,96 +1,274 @@
 package collector
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-type artifact struct {
-	Repo string `json:"repo,omitempty"`
-	Name string `json:"name,omitempty"`
+// decodeAQLResults stream-decodes the "results" array of an AQL response,
+// calling each for every element with the decoder positioned on it, and
+// returns the number of results. The results are never held in memory at once.
+func decodeAQLResults(body io.Reader, each func(dec *json.Decoder) error) (int, error) {
+	dec := json.NewDecoder(body)
+	if err := expectJSONDelim(dec, '{'); err != nil {
+		return 0, err
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return 0, err
+		}
+		if key, _ := token.(string); key != "results" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if err := expectJSONDelim(dec, '['); err != nil {
+			return 0, err
+		}
+		count := 0
+		for dec.More() {
+			if err := each(dec); err != nil {
+				return count, err
+			}
+			count++
+		}
+		return count, expectJSONDelim(dec, ']')
+	}
+	return 0, nil
+}
+
+func expectJSONDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("unexpected JSON token %v, expected %v", token, delim)
+	}
+	return nil
 }
 
-type artifactQueryResult struct {
-	Results []artifact `json:"results,omitempty"`
-	NodeId  string
+// skipAQLResult discards a single AQL result.
+func skipAQLResult(dec *json.Decoder) error {
+	var skip json.RawMessage
+	return dec.Decode(&skip)
 }
 
-func (e *Exporter) findArtifacts(period string, queryType string) (artifactQueryResult, error) {
-	var query string
-	artifacts := artifactQueryResult{}
+// artifactResult is an item returned by the artifacts AQL queries.
+type artifactResult struct {
+	Repo     string  `json:"repo"`
+	Path     string  `json:"path"`
+	Name     string  `json:"name"`
+	Size     float64 `json:"size"`
+	Modified string  `json:"modified"`
+	Stats    []struct {
+		Downloads  float64 `json:"downloads"`
+		Downloaded string  `json:"downloaded"`
+	} `json:"stats"`
+}
+
+func (a artifactResult) downloads() float64 {
+	if len(a.Stats) == 0 {
+		return 0
+	}
+	return a.Stats[0].Downloads
+}
+
+// queryArtifacts runs each for the items of every repository created or
+// downloaded in the period and returns their number. The include lists the
+// fields added to the repo, path and name of the items.
+func (e *Exporter) queryArtifacts(ctx context.Context, period string, queryType string, include string, each func(dec *json.Decoder) error) (float64, error) {
 	e.logger.Debug(
-		"Finding all artifacts",
+		"Finding artifacts",
 		"period", period,
 		"queryType", queryType,
 	)
 	switch queryType {
 	case "created":
-		query = fmt.Sprintf("items.find({\"modified\" : {\"$last\" : \"%s\"}}).include(\"name\", \"repo\")", period)
+		criteria := fmt.Sprintf("{\"modified\" : {\"$last\" : \"%s\"}}", period)
+		return e.queryItemsPaged(ctx, criteria, include, each)
 	case "downloaded":
-		query = fmt.Sprintf("items.find({\"stat.downloaded\" : {\"$last\" : \"%s\"}}).include(\"name\", \"repo\")", period)
+		// Download counters are stat fields, which Artifactory does not page:
+		// the query is not paged and its response is decoded while it is read.
+		query := fmt.Sprintf("items.find({\"stat.downloaded\" : {\"$last\" : \"%s\"}}).include(\"repo\", \"path\", \"name\", %s)", period, include)
+		count, _, err := e.streamAQL(ctx, query, each)
+		return float64(count), err
 	default:
 		e.logger.Error(
 			"Query Type is not supported",
 			"query", queryType,
 		)
-		return artifacts, fmt.Errorf("query Type is not supported: %s", queryType)
+		return 0, fmt.Errorf("query Type is not supported: %s", queryType)
 	}
-	resp, err := e.client.QueryAQL([]byte(query))
+}
+
+// queryItemsPaged runs each for the items matching the AQL criteria and
+// returns their number. The query includes the repo, path and name of the
+// items, which the pages are sorted on, and the given fields. It is paged
+// with .offset()/.limit() and every page is decoded while it is read, so the
+// memory used is bounded by the page size. Artifactory ignores the sort,
+// offset and limit when fields of other domains than items (e.g. stat) are
+// included, so only item fields may be.
+func (e *Exporter) queryItemsPaged(ctx context.Context, criteria string, include string, each func(dec *json.Decoder) error) (float64, error) {
+	if strings.Contains(include, ".") {
+		return 0, fmt.Errorf("paged AQL queries can only include item fields, got %s", include)
+	}
+	pageSize := e.exporterRuntimeConfig.ArtifactsAQLPageSize
+	var total float64
+	for offset := 0; ; offset += pageSize {
+		query := fmt.Sprintf("items.find(%s).include(\"repo\", \"path\", \"name\", %s).sort({\"$asc\" : [\"repo\", \"path\", \"name\"]}).offset(%d).limit(%d)", criteria, include, offset, pageSize)
+		count, _, err := e.streamAQL(ctx, query, each)
+		if err != nil {
+			return total, err
+		}
+		total += float64(count)
+		if count < pageSize {
+			return total, nil
+		}
+	}
+}
+
+// streamAQL runs an AQL query and calls each for every result while the
+// response is read. It returns the number of results and the node id of the
+// response. The query is aborted once ctx is done.
+func (e *Exporter) streamAQL(ctx context.Context, query string, each func(dec *json.Decoder) error) (int, string, error) {
+	body, err := e.client.StreamAQL(ctx, []byte(query))
 	if err != nil {
 		e.totalAPIErrors.Inc()
-		return artifacts, err
+		return 0, "", err
 	}
-	artifacts.NodeId = resp.NodeId
-	if err := json.Unmarshal(resp.Body, &artifacts); err != nil {
+	defer body.Close()
+	count, err := decodeAQLResults(body, each)
+	if err != nil {
 		e.logger.Warn(
-			"There was an error when trying to unmarshal AQL response",
-			"queryType", queryType,
-			"period", period,
+			"There was an error when trying to decode AQL response",
+			"query", query,
 			"error", err.Error(),
 		)
 		e.jsonParseFailures.Inc()
-		return artifacts, err
+		return count, body.NodeId, err
 	}
-	return artifacts, err
+	return count, body.NodeId, nil
 }
 
+// getTotalArtifacts counts the artifacts created and downloaded in each time
+// interval for every repository, with their size. The items of the longest
+// time interval are queried once for all repositories and assigned to the
+// shorter intervals from their timestamps. Downloads are estimated from the
+// deltas of the download counters of the downloaded items.
 func (e *Exporter) getTotalArtifacts(r []repoSummary) ([]repoSummary, error) {
 	repoSummaries := r
 
 	timeIntervals := e.exporterRuntimeConfig.ArtifactsTimeIntervals
+	var retention time.Duration
+	var longestPeriod string
+	for _, timeInterval := range timeIntervals {
+		if timeInterval.Window > retention {
+			retention, longestPeriod = timeInterval.Window, timeInterval.Period
+		}
+	}
+	now := time.Now()
 
 	groupedRepoSummary := make(map[string]*repoSummary, len(repoSummaries))
 	for i := range repoSummaries {
 		repoSummaries[i].RepoArtifactsSummary = make([]RepoArtifactsSummary, len(timeIntervals))
-		// Fill the slice directly
 		for j, timeInterval := range timeIntervals {
 			repoSummaries[i].RepoArtifactsSummary[j] = RepoArtifactsSummary{period: timeInterval.ShortPeriod}
 		}
-		groupedRepoSummary[repoSummaries[i].Name] = &repoSummaries[i]
+		// Virtual repositories hold no items of their own
+		if repoSummaries[i].Type != "virtual" {
+			groupedRepoSummary[repoSummaries[i].Name] = &repoSummaries[i]
+		}
+	}
+	if retention == 0 {
+		return repoSummaries, nil
 	}
 
-	for intervalIdx, timeInterval := range timeIntervals {
-		created, err := e.findArtifacts(timeInterval.Period, "created")
-		if err != nil {
-			return nil, err
+	_, err := e.queryArtifacts(context.Background(), longestPeriod, "created", `"size", "modified"`, func(dec *json.Decoder) error {
+		var item artifactResult
+		if err := dec.Decode(&item); err != nil {
+			return err
 		}
-		downloaded, err := e.findArtifacts(timeInterval.Period, "downloaded")
+		repo, exists := groupedRepoSummary[item.Repo]
+		if !exists {
+			return nil
+		}
+		modified, err := time.Parse(time.RFC3339, item.Modified)
 		if err != nil {
-			return nil, err
+			return err
 		}
-
-		for _, item := range created.Results {
-			if repo, exists := groupedRepoSummary[item.Repo]; exists {
-				repo.RepoArtifactsSummary[intervalIdx].TotalCreated++
+		for j, timeInterval := range timeIntervals {
+			if modified.Before(now.Add(-timeInterval.Window)) {
+				continue
 			}
+			summary := &repo.RepoArtifactsSummary[j]
+			summary.TotalCreated++
+			summary.CreatedBytes += item.Size
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// map[<repo>]map[<path>/<name>] <download counter> of the items downloaded
+	// within the longest time interval. Once maxTrackedDownloads items are
+	// collected, only the items the tracker already keeps are added. Every
+	// repository is listed so that the tracker keeps its other items.
+	current := make(map[string]map[string]float64, len(groupedRepoSummary))
+	for name := range groupedRepoSummary {
+		current[name] = map[string]float64{}
+	}
+	currentItems := 0
+	_, err = e.queryArtifacts(context.Background(), longestPeriod, "downloaded", `"size", "stat.downloads", "stat.downloaded"`, func(dec *json.Decoder) error {
+		var item artifactResult
+		if err := dec.Decode(&item); err != nil {
+			return err
 		}
-		for _, item := range downloaded.Results {
-			if repo, exists := groupedRepoSummary[item.Repo]; exists {
-				repo.RepoArtifactsSummary[intervalIdx].TotalDownloaded++
+		repo, exists := groupedRepoSummary[item.Repo]
+		if !exists || len(item.Stats) == 0 {
+			return nil
+		}
+		downloaded, err := time.Parse(time.RFC3339, item.Stats[0].Downloaded)
+		if err != nil {
+			return err
+		}
+		key := item.Path + "/" + item.Name
+		for j, timeInterval := range timeIntervals {
+			if downloaded.Before(now.Add(-timeInterval.Window)) {
+				continue
 			}
+			downloads := e.downloads.estimate(item.Repo, key, item.downloads(), now, timeInterval.Window)
+			summary := &repo.RepoArtifactsSummary[j]
+			summary.TotalDownloaded++
+			summary.Downloads += downloads
+			summary.DownloadedBytes += downloads * item.Size
+		}
+		if currentItems >= maxTrackedDownloads && !e.downloads.tracks(item.Repo, key) {
+			return nil
 		}
+		current[item.Repo][key] = item.downloads()
+		currentItems++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if untracked := e.downloads.record(current, now, retention); untracked > 0 {
+		e.logger.Warn(
+			"Too many downloaded artifacts, their downloads are estimated without history",
+			"limit", maxTrackedDownloads,
+			"untracked", untracked,
+		)
 	}
 
 	return repoSummaries, nil
@@ -123,6 +301,22 @@ func (e *Exporter) exportArtifacts(repoSummaries []repoSummary, ch chan<- promet
 			)
 			downloadedMetric := artifactsMetrics[downloadedMetricName]
 			ch <- prometheus.MustNewConstMetric(downloadedMetric, prometheus.GaugeValue, repoArtifactsSummary.TotalDownloaded, repoSummary.Name, repoSummary.Type, repoSummary.PackageType, repoSummary.NodeId)
+
+			for metricName, value := range map[string]float64{
+				fmt.Sprintf("created_bytes_%s", repoArtifactsSummary.period):    repoArtifactsSummary.CreatedBytes,
+				fmt.Sprintf("downloads_%s", repoArtifactsSummary.period):        repoArtifactsSummary.Downloads,
+				fmt.Sprintf("downloaded_bytes_%s", repoArtifactsSummary.period): repoArtifactsSummary.DownloadedBytes,
+			} {
+				e.logger.Debug(
+					logDbgMsgRegMetric,
+					"metric", metricName,
+					"repo", repoSummary.Name,
+					"type", repoSummary.Type,
+					"package_type", repoSummary.PackageType,
+					"value", value,
+				)
+				ch <- prometheus.MustNewConstMetric(artifactsMetrics[metricName], prometheus.GaugeValue, value, repoSummary.Name, repoSummary.Type, repoSummary.PackageType, repoSummary.NodeId)
+			}
 		}
 	}
 }
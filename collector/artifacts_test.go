@@ -0,0 +1,107 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDecodeAQLResults(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    int
+		wantErr bool
+	}{
+		{"Results before range", `{"results":[{"name":"a"},{"name":"b"}],"range":{"total":2}}`, 2, false},
+		{"Range before results", `{"range":{"total":1},"results":[{"name":"a"}]}`, 1, false},
+		{"Empty results", `{"results":[],"range":{"total":0}}`, 0, false},
+		{"No results", `{"range":{"total":0}}`, 0, false},
+		{"Not an object", `[]`, 0, true},
+		{"Truncated", `{"results":[{"name":"a"},`, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeAQLResults(strings.NewReader(tt.body), func(dec *json.Decoder) error {
+				return skipAQLResult(dec)
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeAQLResults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("decodeAQLResults() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountArtifactsPaging(t *testing.T) {
+	const items = 5
+	offsetRe := regexp.MustCompile(`\.offset\((\d+)\)\.limit\((\d+)\)`)
+	queries := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `.include("repo", "path", "name", "size").sort({"$asc" : ["repo", "path", "name"]})`) {
+			t.Errorf("Expected pages sorted on included item fields, got %s", body)
+		}
+		m := offsetRe.FindStringSubmatch(string(body))
+		if m == nil {
+			t.Fatalf("Expected a paged query, got %s", body)
+		}
+		queries++
+		offset, _ := strconv.Atoi(m[1])
+		limit, _ := strconv.Atoi(m[2])
+		var results []string
+		for i := offset; i < items && i < offset+limit; i++ {
+			results = append(results, `{"name":"item`+strconv.Itoa(i)+`"}`)
+		}
+		w.Write([]byte(`{"results":[` + strings.Join(results, ",") + `]}`))
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+	e.exporterRuntimeConfig.ArtifactsAQLPageSize = 2
+
+	count, err := e.queryArtifacts(context.Background(), "5minutes", "created", `"size"`, skipAQLResult)
+	if err != nil {
+		t.Fatalf("queryArtifacts() error = %v", err)
+	}
+	if count != items {
+		t.Errorf("queryArtifacts() = %v, want %d", count, items)
+	}
+	if queries != 3 {
+		t.Errorf("Expected 3 pages to be queried, got %d", queries)
+	}
+}
+
+func TestQueryDownloadedArtifactsNotPaged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		// Artifactory ignores sort, offset and limit when stat fields are included
+		if strings.Contains(string(body), "stat.") && regexp.MustCompile(`\.(sort|offset|limit)\(`).Match(body) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors":[{"status":400,"message":"sort, offset and limit need primary domain fields only"}]}`))
+			return
+		}
+		w.Write([]byte(`{"results":[
+			{"repo":"libs-release","path":"org/lib","name":"a.jar","size":10,"stats":[{"downloads":3,"downloaded":"2024-01-01T00:00:00.000Z"}]},
+			{"repo":"libs-release","path":"org/lib","name":"b.jar","size":20,"stats":[{"downloads":1,"downloaded":"2024-01-01T00:00:00.000Z"}]}
+		]}`))
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+	e.exporterRuntimeConfig.ArtifactsAQLPageSize = 1
+
+	count, err := e.queryArtifacts(context.Background(), "15minutes", "downloaded", `"size", "stat.downloads", "stat.downloaded"`, skipAQLResult)
+	if err != nil {
+		t.Fatalf("queryArtifacts() error = %v", err)
+	}
+	if count != 2 {
+		t.Errorf("queryArtifacts() = %v, want 2", count)
+	}
+}
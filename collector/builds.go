@@ -0,0 +1,119 @@
+package collector
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// maxBuildNameSeries bounds the number of build names exported with their own
+// series. The number of build names and build info records stay exact.
+const maxBuildNameSeries = 1000
+
+// buildTimeLayout is the layout of the build start times returned by the
+// build endpoints, e.g. 2024-01-02T10:00:00.000+0000.
+const buildTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// newBuildsCollector returns a scheduled collector counting the builds of
+// every build name matching the build name filter.
+func (e *Exporter) newBuildsCollector() *scheduledCollector {
+	return &scheduledCollector{
+		name:     collectorBuilds,
+		interval: e.exporterRuntimeConfig.BuildsInterval,
+		descs: []*prometheus.Desc{
+			buildMetrics["names"],
+			buildMetrics["builds"],
+			buildMetrics["latestBuildAge"],
+			buildMetrics["infoRecords"],
+		},
+		collect: e.exportBuilds,
+	}
+}
+
+// exportBuilds exports the number of builds and the age of the latest build
+// of each build name. Listing the builds of a build name takes one API call,
+// so only the build names matching the filter are queried. At most maxBuildNameSeries build names, the most recently started first,
+// get their own series.
+func (e *Exporter) exportBuilds(ctx context.Context, ch chan<- prometheus.Metric) error {
+	builds, err := e.client.FetchBuilds()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching builds",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return err
+	}
+	filter := e.exporterRuntimeConfig.BuildNameFilter
+	now := time.Now()
+
+	var matching []artifactory.Build
+	for _, build := range builds.Builds {
+		if filter == nil || filter.MatchString(build.Name()) {
+			matching = append(matching, build)
+		}
+	}
+	buildRuns := fetchEach(ctx, e, collectorBuilds, matching,
+		func(build artifactory.Build) string { return build.Name() },
+		func(build artifactory.Build) ([]artifactory.BuildRun, error) {
+			return e.client.FetchBuildRuns(build.Name())
+		},
+	)
+	var records float64
+	for _, runs := range buildRuns {
+		records += float64(len(runs.value))
+	}
+	ch <- prometheus.MustNewConstMetric(buildMetrics["names"], prometheus.GaugeValue, float64(len(matching)), builds.NodeId)
+	ch <- prometheus.MustNewConstMetric(buildMetrics["infoRecords"], prometheus.GaugeValue, records, builds.NodeId)
+
+	lastStarted := make(map[string]time.Time, len(buildRuns))
+	for _, runs := range buildRuns {
+		started, err := parseBuildTime(runs.item.LastStarted)
+		if err != nil {
+			e.logger.Warn(
+				"Couldn't parse the start time of the latest build",
+				"build", runs.item.Name(),
+				"err", err.Error(),
+			)
+			continue
+		}
+		lastStarted[runs.item.Name()] = started
+	}
+	sort.SliceStable(buildRuns, func(i, j int) bool {
+		return lastStarted[buildRuns[i].item.Name()].After(lastStarted[buildRuns[j].item.Name()])
+	})
+	if len(buildRuns) > maxBuildNameSeries {
+		e.logger.Warn(
+			"Too many build names, skipping the per build name metrics of the least recently started ones",
+			"limit", maxBuildNameSeries,
+			"names", len(buildRuns),
+		)
+		buildRuns = buildRuns[:maxBuildNameSeries]
+	}
+	for _, runs := range buildRuns {
+		name := runs.item.Name()
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "builds",
+			"build", name,
+			"value", len(runs.value),
+		)
+		ch <- prometheus.MustNewConstMetric(buildMetrics["builds"], prometheus.GaugeValue, float64(len(runs.value)), name, builds.NodeId)
+		if started, ok := lastStarted[name]; ok {
+			ch <- prometheus.MustNewConstMetric(buildMetrics["latestBuildAge"], prometheus.GaugeValue, now.Sub(started).Seconds(), name, builds.NodeId)
+		}
+	}
+	return nil
+}
+
+// parseBuildTime parses a build start time, falling back to RFC 3339.
+func parseBuildTime(value string) (time.Time, error) {
+	if t, err := time.Parse(buildTimeLayout, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
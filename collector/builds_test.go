@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestExportBuilds(t *testing.T) {
+	lastStarted := time.Now().Add(-2 * time.Hour).UTC().Format(buildTimeLayout)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/build":
+			w.Write([]byte(`{"builds":[{"uri":"/release-app","lastStarted":"` + lastStarted + `"},{"uri":"/feature-app","lastStarted":"` + lastStarted + `"},{"uri":"/release-old","lastStarted":"` + lastStarted + `"}]}`))
+		case "/api/build/release-app":
+			w.Write([]byte(`{"buildsNumbers":[{"uri":"/2"},{"uri":"/1"}]}`))
+		case "/api/build/release-old":
+			// Deleted since the build names were listed
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+	e.exporterRuntimeConfig.BuildNameFilter = regexp.MustCompile("^release-")
+
+	ch := make(chan prometheus.Metric, 10)
+	if err := e.track(collectorBuilds, func() error { return e.exportBuilds(context.Background(), ch) }); err != nil {
+		t.Fatalf("exportBuilds() error = %v", err)
+	}
+	close(ch)
+	values := map[string]float64{}
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		values[m.Desc().String()] = metric.GetGauge().GetValue()
+	}
+	if got := values[buildMetrics["builds"].String()]; got != 2 {
+		t.Errorf("builds = %v, want 2", got)
+	}
+	if got := values[buildMetrics["names"].String()]; got != 2 {
+		t.Errorf("names = %v, want 2", got)
+	}
+	if got := values[buildMetrics["infoRecords"].String()]; got != 2 {
+		t.Errorf("info records = %v, want 2", got)
+	}
+	if got := values[buildMetrics["latestBuildAge"].String()]; got < 7200 || got > 7300 {
+		t.Errorf("latest build age = %v, want about 7200", got)
+	}
+	if skipped := e.Status().Collectors[collectorBuilds].LastSkipped; skipped != 1 {
+		t.Errorf("LastSkipped = %d, want 1", skipped)
+	}
+}
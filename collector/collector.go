@@ -3,8 +3,11 @@ package collector
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
 )
 
 const (
@@ -13,12 +16,21 @@ const (
 
 // Label sets reused across metrics for consistency and reduced duplication
 var (
-	defaultLabelNames     = []string{"node_id"}
-	filestoreLabelNames   = append([]string{"storage_type", "storage_dir"}, defaultLabelNames...)
-	repoLabelNames        = append([]string{"name", "type", "package_type"}, defaultLabelNames...)
-	replicationLabelNames = append([]string{"name", "type", "url", "cron_exp", "status"}, defaultLabelNames...)
-	federationLabelNames  = append([]string{"name", "remote_url", "remote_name"}, defaultLabelNames...)
-	certificateLabelNames = append([]string{"alias", "issued_by", "expires"}, defaultLabelNames...)
+	defaultLabelNames        = []string{"node_id"}
+	filestoreLabelNames      = append([]string{"storage_type", "storage_dir"}, defaultLabelNames...)
+	repoLabelNames           = append([]string{"name", "type", "package_type"}, defaultLabelNames...)
+	replicationLabelNames    = append([]string{"name", "type", "url", "cron_exp", "status"}, defaultLabelNames...)
+	federationLabelNames     = append([]string{"name", "remote_url", "remote_name"}, defaultLabelNames...)
+	certificateLabelNames    = append([]string{"alias", "issued_by", "expires"}, defaultLabelNames...)
+	gpgKeyLabelNames         = append([]string{"type", "key_id", "alias", "expires"}, defaultLabelNames...)
+	remoteRepoLabelNames     = append([]string{"name", "package_type", "url"}, defaultLabelNames...)
+	repoConfigLabelNames     = append([]string{"name", "rclass", "package_type", "xray_index", "handle_releases", "handle_snapshots", "blacked_out", "offline"}, defaultLabelNames...)
+	projectLabelNames        = append([]string{"project"}, defaultLabelNames...)
+	topArtifactLabelNames    = append([]string{"repo", "path", "name"}, defaultLabelNames...)
+	staleArtifactsLabelNames = append([]string{"name", "type", "days", "never_downloaded"}, defaultLabelNames...)
+	dockerRepoLabelNames     = append([]string{"repo", "type"}, defaultLabelNames...)
+	dockerImageLabelNames    = append([]string{"repo", "image"}, defaultLabelNames...)
+	buildLabelNames          = append([]string{"name"}, defaultLabelNames...)
 )
 
 // Helper for creating new metric descriptors
@@ -40,20 +52,34 @@ var (
 		"certificates": newMetric("certificates", "security", "Internal SSL certificate information, seconds to expiration as value", certificateLabelNames),
 	}
 
+	permissionTargetMetrics = metrics{
+		"targets":             newMetric("permission_targets", "security", "Number of Artifactory permission targets.", defaultLabelNames),
+		"uncoveredRepos":      newMetric("repos_without_permission_target", "security", "Number of repositories covered by no permission target.", defaultLabelNames),
+		"targetsMissingRepos": newMetric("permission_targets_with_missing_repos", "security", "Number of permission targets referencing repositories which do not exist.", defaultLabelNames),
+		"principals":          newMetric("permission_principals", "security", "Number of users or groups granted an action (delete or manage) by at least one permission target.", append([]string{"type", "action"}, defaultLabelNames...)),
+	}
+
 	storageMetrics = metrics{
-		"artifacts":      newMetric("artifacts", "storage", "Total artifacts count stored in Artifactory.", defaultLabelNames),
-		"artifactsSize":  newMetric("artifacts_size_bytes", "storage", "Total artifacts Size stored in Artifactory in bytes.", defaultLabelNames),
-		"binaries":       newMetric("binaries", "storage", "Total binaries count stored in Artifactory.", defaultLabelNames),
-		"binariesSize":   newMetric("binaries_size_bytes", "storage", "Total binaries Size stored in Artifactory in bytes.", defaultLabelNames),
-		"filestore":      newMetric("filestore_bytes", "storage", "Total available space in the file store in bytes.", filestoreLabelNames),
-		"filestoreUsed":  newMetric("filestore_used_bytes", "storage", "Used space in the file store in bytes.", filestoreLabelNames),
-		"filestoreFree":  newMetric("filestore_free_bytes", "storage", "Free space in the file store in bytes.", filestoreLabelNames),
-		"items":          newMetric("items", "storage", "Total items count stored in Artifactory.", defaultLabelNames),
-		"repoUsed":       newMetric("repo_used_bytes", "storage", "Used space by an Artifactory repository in bytes.", repoLabelNames),
-		"repoFolders":    newMetric("repo_folders", "storage", "Number of folders in an Artifactory repository.", repoLabelNames),
-		"repoFiles":      newMetric("repo_files", "storage", "Number of files in an Artifactory repository.", repoLabelNames),
-		"repoItems":      newMetric("repo_items", "storage", "Number of items in an Artifactory repository.", repoLabelNames),
-		"repoPercentage": newMetric("repo_percentage", "storage", "Percentage of space used by an Artifactory repository.", repoLabelNames),
+		"artifacts":          newMetric("artifacts", "storage", "Total artifacts count stored in Artifactory.", defaultLabelNames),
+		"artifactsSize":      newMetric("artifacts_size_bytes", "storage", "Total artifacts Size stored in Artifactory in bytes.", defaultLabelNames),
+		"binaries":           newMetric("binaries", "storage", "Total binaries count stored in Artifactory.", defaultLabelNames),
+		"binariesSize":       newMetric("binaries_size_bytes", "storage", "Total binaries Size stored in Artifactory in bytes.", defaultLabelNames),
+		"filestore":          newMetric("filestore_bytes", "storage", "Total available space in the file store in bytes.", filestoreLabelNames),
+		"filestoreUsed":      newMetric("filestore_used_bytes", "storage", "Used space in the file store in bytes.", filestoreLabelNames),
+		"filestoreFree":      newMetric("filestore_free_bytes", "storage", "Free space in the file store in bytes.", filestoreLabelNames),
+		"filestoreUsedRatio": newMetric("filestore_used_ratio", "storage", "Used space in the file store as a ratio of its total space (0-1), as reported by Artifactory.", filestoreLabelNames),
+		"items":              newMetric("items", "storage", "Total items count stored in Artifactory.", defaultLabelNames),
+		"repoUsed":           newMetric("repo_used_bytes", "storage", "Used space by an Artifactory repository in bytes.", repoLabelNames),
+		"repoFolders":        newMetric("repo_folders", "storage", "Number of folders in an Artifactory repository.", repoLabelNames),
+		"repoFiles":          newMetric("repo_files", "storage", "Number of files in an Artifactory repository.", repoLabelNames),
+		"repoItems":          newMetric("repo_items", "storage", "Number of items in an Artifactory repository.", repoLabelNames),
+		"repoPercentage":     newMetric("repo_percentage", "storage", "Percentage of space used by an Artifactory repository.", repoLabelNames),
+	}
+
+	storageInfoFreshnessMetrics = metrics{
+		"age":       newMetric("info_age_seconds", "storage", "Seconds since the last storage summary calculation requested by the exporter completed.", defaultLabelNames),
+		"requested": newMetric("info_calculation_requested_timestamp_seconds", "storage", "Unix timestamp of the last storage summary calculation requested by the exporter.", defaultLabelNames),
+		"completed": newMetric("info_calculation_completed_timestamp_seconds", "storage", "Unix timestamp at which the result of the last requested storage summary calculation was first observed.", defaultLabelNames),
 	}
 
 	systemMetrics = metrics{
@@ -77,6 +103,105 @@ var (
 	accessMetrics = metrics{
 		"accessFederationValid": newMetric("access_federation_valid", "access", "Is JFrog Access Federation valid (1 = Circle of Trust validated)", defaultLabelNames),
 	}
+
+	repositoriesMetrics = metrics{
+		"count": newMetric("count", "repositories", "Number of Artifactory repositories by rclass and package type.", append([]string{"rclass", "package_type"}, defaultLabelNames...)),
+		"info":  newMetric("info", "repositories", "Configuration flags of an Artifactory repository as labels.", repoConfigLabelNames),
+	}
+
+	remoteRepoMetrics = metrics{
+		"online": newMetric("online", "remote_repo", "Is the remote repository configured online (1 = online, 0 = set offline or blacked out in its configuration).", remoteRepoLabelNames),
+		"status": newMetric("status", "remote_repo", "Configured status of the remote repository (online, offline or blacked_out) as label.", append([]string{"name", "package_type", "url", "status"}, defaultLabelNames...)),
+	}
+
+	upstreamProbeMetrics = metrics{
+		"urlReachable":     newMetric("url_reachable", "remote_repo", "Did the upstream URL of the remote repository answer a HEAD request (1 = reachable).", remoteRepoLabelNames),
+		"urlProbeDuration": newMetric("url_probe_duration_seconds", "remote_repo", "Duration of the HEAD request to the upstream URL of the remote repository.", remoteRepoLabelNames),
+	}
+
+	virtualRepoMetrics = metrics{
+		"members":               newMetric("members", "virtual_repo", "Number of member repositories of a virtual repository.", append([]string{"name", "package_type"}, defaultLabelNames...)),
+		"defaultDeploymentRepo": newMetric("default_deployment_repo", "virtual_repo", "Default deployment repository of a virtual repository as label.", append([]string{"name", "package_type", "default_deployment_repo"}, defaultLabelNames...)),
+		"member":                newMetric("member", "virtual_repo", "Member repository of a virtual repository, with its rclass and status (online, offline, blacked_out or missing) as labels.", append([]string{"name", "package_type", "member", "member_rclass", "member_status"}, defaultLabelNames...)),
+	}
+
+	storageGrowthMetrics = metrics{
+		"repoGrowth":             newMetric("repo_growth_bytes_per_day", "storage", "Growth of the space used by an Artifactory repository in bytes per day over the storage growth window.", repoLabelNames),
+		"repoItemsGrowth":        newMetric("repo_items_growth_per_day", "storage", "Growth of the number of items in an Artifactory repository per day over the storage growth window.", repoLabelNames),
+		"filestoreGrowth":        newMetric("filestore_growth_bytes_per_day", "storage", "Growth of the space used in the file store in bytes per day over the storage growth window.", filestoreLabelNames),
+		"filestoreDaysUntilFull": newMetric("filestore_days_until_full", "storage", "Days until the file store is full at its current growth rate, only exported while it grows.", filestoreLabelNames),
+	}
+
+	staleArtifactsMetrics = metrics{
+		"count": newMetric("artifacts", "stale", "Number of artifacts created before and not downloaded within the last days.", staleArtifactsLabelNames),
+		"bytes": newMetric("artifacts_bytes", "stale", "Size of the artifacts created before and not downloaded within the last days in bytes.", staleArtifactsLabelNames),
+	}
+
+	topArtifactsMetrics = metrics{
+		"size":      newMetric("size_bytes", "top_artifact", "Size of one of the largest artifacts in bytes.", topArtifactLabelNames),
+		"downloads": newMetric("downloads", "top_artifact", "Number of downloads of one of the most downloaded artifacts.", topArtifactLabelNames),
+	}
+
+	artifactSizeMetrics = metrics{
+		"size": newMetric("size_bytes", "artifact", "Size distribution of the artifacts of a repository in bytes.", append([]string{"repo", "type"}, defaultLabelNames...)),
+	}
+
+	dockerMetrics = metrics{
+		"images":       newMetric("images", "docker", "Number of Docker images of a repository.", dockerRepoLabelNames),
+		"tags":         newMetric("tags", "docker", "Number of Docker tags of a repository.", dockerRepoLabelNames),
+		"imageTags":    newMetric("image_tags", "docker", "Number of tags of a Docker image.", dockerImageLabelNames),
+		"imageSize":    newMetric("image_size_bytes", "docker", "Size of the distinct layers referenced by the manifests of the tags of a Docker image in bytes.", dockerImageLabelNames),
+		"oldestTagAge": newMetric("image_oldest_tag_age_seconds", "docker", "Age of the oldest tag of a Docker image in seconds.", dockerImageLabelNames),
+		"newestTagAge": newMetric("image_newest_tag_age_seconds", "docker", "Age of the newest tag of a Docker image in seconds.", dockerImageLabelNames),
+	}
+
+	buildMetrics = metrics{
+		"names":          newMetric("names", "build", "Number of build names.", defaultLabelNames),
+		"builds":         newMetric("builds", "build", "Number of builds of a build name.", buildLabelNames),
+		"latestBuildAge": newMetric("latest_build_age_seconds", "build", "Age of the latest build of a build name in seconds.", buildLabelNames),
+		"infoRecords":    newMetric("info_records", "build", "Total number of build-info records of all the build names.", defaultLabelNames),
+	}
+
+	userDetailsMetrics = metrics{
+		"admins":           newMetric("admin_users", "security", "Number of Artifactory users with admin rights.", defaultLabelNames),
+		"locked":           newMetric("locked_users", "security", "Number of Artifactory users locked out after too many failed login attempts.", defaultLabelNames),
+		"disabled":         newMetric("disabled_users", "security", "Number of disabled Artifactory users.", defaultLabelNames),
+		"inactive":         newMetric("inactive_users", "security", "Number of Artifactory users who did not log in within the last days, including users who never logged in.", append([]string{"days"}, defaultLabelNames...)),
+		"passwordExpiring": newMetric("password_expiring_users", "security", "Number of Artifactory users whose password expires within the next days or has expired.", append([]string{"days"}, defaultLabelNames...)),
+	}
+
+	gpgKeyMetrics = metrics{
+		"keys": newMetric("gpg_keys", "security", "GPG signing and trusted key information, seconds to expiration as value", gpgKeyLabelNames),
+	}
+
+	groupDetailsMetrics = metrics{
+		"info":        newMetric("group_info", "security", "Artifactory group with its realm, auto-join and admin privileges as labels.", append([]string{"name", "realm", "auto_join", "admin_privileges"}, defaultLabelNames...)),
+		"members":     newMetric("group_members", "security", "Number of members of an Artifactory group.", append([]string{"name", "realm"}, defaultLabelNames...)),
+		"emptyGroups": newMetric("empty_groups", "security", "Number of Artifactory groups without members.", defaultLabelNames),
+		"adminGroups": newMetric("admin_groups", "security", "Number of Artifactory groups granting admin privileges to their members.", defaultLabelNames),
+	}
+
+	accessTokenMetrics = metrics{
+		"tokens":        newMetric("tokens", "access", "Number of access tokens by subject type.", append([]string{"subject_type"}, defaultLabelNames...)),
+		"withoutExpiry": newMetric("tokens_without_expiry", "access", "Number of access tokens which never expire.", defaultLabelNames),
+		"expiring":      newMetric("tokens_expiring", "access", "Number of access tokens expiring within the next days.", append([]string{"days"}, defaultLabelNames...)),
+		"refreshable":   newMetric("tokens_refreshable", "access", "Number of access tokens by whether they are refreshable.", append([]string{"refreshable"}, defaultLabelNames...)),
+	}
+
+	projectMetrics = metrics{
+		"info":             newMetric("info", "project", "JFrog project with its display name as label.", append([]string{"project", "display_name"}, defaultLabelNames...)),
+		"repositories":     newMetric("repositories", "project", "Number of repositories assigned to the project.", projectLabelNames),
+		"usedBytes":        newMetric("used_bytes", "project", "Space used by the repositories of the project in bytes.", projectLabelNames),
+		"quotaBytes":       newMetric("storage_quota_bytes", "project", "Storage quota of the project in bytes, only exported for projects with a quota.", projectLabelNames),
+		"quotaUtilization": newMetric("storage_quota_utilization_ratio", "project", "Space used by the repositories of the project as a ratio of its storage quota.", projectLabelNames),
+		"members":          newMetric("members", "project", "Number of members of the project by member type (user or group).", append([]string{"project", "type"}, defaultLabelNames...)),
+		"roleMembers":      newMetric("role_members", "project", "Number of members holding a role in the project by member type (user or group).", append([]string{"project", "role", "type"}, defaultLabelNames...)),
+	}
+
+	cacheMetrics = metrics{
+		"notModified": newMetric("cache_not_modified_total", "exporter", "Number of cached API responses revalidated with 304 Not Modified.", nil),
+		"savedBytes":  newMetric("cache_saved_bytes_total", "exporter", "Response body bytes not transferred thanks to cache revalidation.", nil),
+	}
 )
 
 func InitMetrics(e *Exporter) {
@@ -88,6 +213,17 @@ func InitMetrics(e *Exporter) {
 		e.logger.Debug("Init metric", "metricName", createdMetricName)
 		artifactsMetrics[downloadedMetricName] = newMetric(downloadedMetricName, "artifacts", fmt.Sprintf("Number of artifacts downloaded from the repository in the last %d %s.", timeInterval.Duration, timeInterval.Unit), repoLabelNames)
 		e.logger.Debug("Init metric", "metricName", downloadedMetricName)
+
+		createdBytesMetricName := fmt.Sprintf("created_bytes_%s", timeInterval.ShortPeriod)
+		downloadsMetricName := fmt.Sprintf("downloads_%s", timeInterval.ShortPeriod)
+		downloadedBytesMetricName := fmt.Sprintf("downloaded_bytes_%s", timeInterval.ShortPeriod)
+
+		artifactsMetrics[createdBytesMetricName] = newMetric(createdBytesMetricName, "artifacts", fmt.Sprintf("Size of the artifacts created in the repository in the last %d %s in bytes.", timeInterval.Duration, timeInterval.Unit), repoLabelNames)
+		e.logger.Debug("Init metric", "metricName", createdBytesMetricName)
+		artifactsMetrics[downloadsMetricName] = newMetric(downloadsMetricName, "artifacts", fmt.Sprintf("Estimated number of downloads from the repository in the last %d %s.", timeInterval.Duration, timeInterval.Unit), repoLabelNames)
+		e.logger.Debug("Init metric", "metricName", downloadsMetricName)
+		artifactsMetrics[downloadedBytesMetricName] = newMetric(downloadedBytesMetricName, "artifacts", fmt.Sprintf("Estimated number of bytes downloaded from the repository in the last %d %s.", timeInterval.Duration, timeInterval.Unit), repoLabelNames)
+		e.logger.Debug("Init metric", "metricName", downloadedBytesMetricName)
 	}
 }
 
@@ -107,6 +243,16 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	for _, m := range systemMetrics {
 		ch <- m
 	}
+	if e.exporterRuntimeConfig.StorageInfoRefreshInterval > 0 {
+		for _, m := range storageInfoFreshnessMetrics {
+			ch <- m
+		}
+	}
+	if e.storageHistory != nil {
+		for _, m := range storageGrowthMetrics {
+			ch <- m
+		}
+	}
 	if e.exporterRuntimeConfig.OptionalMetrics.Artifacts {
 		for _, m := range artifactsMetrics {
 			ch <- m
@@ -127,6 +273,42 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 			ch <- m
 		}
 	}
+	if e.exporterRuntimeConfig.OptionalMetrics.Repositories {
+		for _, m := range repositoriesMetrics {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.RemoteRepositories {
+		for _, m := range remoteRepoMetrics {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.VirtualRepositories {
+		for _, m := range virtualRepoMetrics {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.Projects {
+		for _, m := range projectMetrics {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.AccessTokens {
+		for _, m := range accessTokenMetrics {
+			ch <- m
+		}
+	}
+	if e.exporterRuntimeConfig.OptionalMetrics.GPGKeys {
+		for _, m := range gpgKeyMetrics {
+			ch <- m
+		}
+	}
+	for _, s := range e.scheduled {
+		s.describe(ch)
+	}
+	for _, m := range cacheMetrics {
+		ch <- m
+	}
 	ch <- e.up.Desc()
 	ch <- e.totalScrapes.Desc()
 	ch <- e.totalAPIErrors.Desc()
@@ -150,6 +332,10 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	ch <- e.totalScrapes
 	ch <- e.totalAPIErrors
 	ch <- e.jsonParseFailures
+	e.exportCacheStats(ch)
+	for _, s := range e.scheduled {
+		s.export(ch)
+	}
 
 	// Manually collect background task metrics from the GaugeVec
 	e.backgroundTaskMetrics.Collect(ch)
@@ -158,13 +344,17 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 // scrape executes metric collection logic, split into helper functions to reduce complexity.
 func (e *Exporter) scrape(ch chan<- prometheus.Metric) float64 {
 	e.totalScrapes.Inc()
+	start := time.Now()
+	defer func() { e.status.recordScrape(start, time.Since(start)) }()
 
 	// Reset the metric to avoid duplicate data
 	e.backgroundTaskMetrics.Reset()
+	e.client.ResetLastPayloads()
 
 	if !e.runExportSteps(ch) {
 		return 0
 	}
+	e.status.recordReady(nil)
 
 	if e.exporterRuntimeConfig.OptionalMetrics.BackgroundTasks {
 		e.collectBackgroundTasks()
@@ -175,57 +365,124 @@ func (e *Exporter) scrape(ch chan<- prometheus.Metric) float64 {
 
 // runExportSteps performs the main metric collection sequence.
 // Returns false if any required step fails.
+// The outcome of each step is recorded for the status endpoint.
 func (e *Exporter) runExportSteps(ch chan<- prometheus.Metric) bool {
 	if e.exporterRuntimeConfig.OptionalMetrics.OpenMetrics {
-		if err := e.exportOpenMetrics(ch); err != nil {
+		if err := e.track(collectorOpenMetrics, func() error { return e.exportOpenMetrics(ch) }); err != nil {
 			return false
 		}
 	}
-	if err := e.exportSystem(ch); err != nil {
+	if err := e.track(collectorSystem, func() error { return e.exportSystem(ch) }); err != nil {
 		return false
 	}
-	if err := e.exportSystemHALicenses(ch); err != nil {
+	if err := e.track(collectorLicenses, func() error { return e.exportSystemHALicenses(ch) }); err != nil {
 		return false
 	}
 
-	storageInfo, err := e.client.FetchStorageInfo()
-	if err != nil {
-		e.totalAPIErrors.Inc()
-		return false
-	}
-	e.exportStorage(storageInfo, ch)
+	var repoSummaryList []repoSummary
+	err := e.track(collectorStorage, func() error {
+		storageInfo, err := e.client.FetchStorageInfo()
+		if err != nil {
+			e.totalAPIErrors.Inc()
+			return err
+		}
+		e.exportStorage(storageInfo, ch)
+		if e.exporterRuntimeConfig.StorageInfoRefreshInterval > 0 {
+			e.exportStorageInfoFreshness(storageInfo.NodeId, ch)
+		}
 
-	repoSummaryList, err := e.extractRepo(storageInfo)
+		repoSummaryList, err = e.extractRepo(storageInfo)
+		if err != nil {
+			return err
+		}
+		e.exportRepo(repoSummaryList, ch)
+		if e.storageHistory != nil {
+			e.exportStorageGrowth(storageInfo, repoSummaryList, ch)
+		}
+		return nil
+	})
 	if err != nil {
 		return false
 	}
-	e.exportRepo(repoSummaryList, ch)
 
 	if e.exporterRuntimeConfig.OptionalMetrics.Artifacts {
-		repoSummaryList, err = e.getTotalArtifacts(repoSummaryList)
+		err := e.track(collectorArtifacts, func() error {
+			repoSummaryList, err := e.getTotalArtifacts(repoSummaryList)
+			if err != nil {
+				return err
+			}
+			e.exportArtifacts(repoSummaryList, ch)
+			return nil
+		})
 		if err != nil {
 			return false
 		}
-		e.exportArtifacts(repoSummaryList, ch)
 	}
 
 	if e.exporterRuntimeConfig.OptionalMetrics.FederationStatus && e.client.IsFederationEnabled() {
-		e.exportFederationMirrorLags(ch)
-		e.exportFederationUnavailableMirrors(ch)
+		e.track(collectorFederation, func() error {
+			err := e.exportFederationMirrorLags(ch)
+			if unavailableErr := e.exportFederationUnavailableMirrors(ch); err == nil {
+				err = unavailableErr
+			}
+			return err
+		})
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.Repositories {
+		e.track(collectorRepositories, func() error { return e.exportRepositories(ch) })
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.RemoteRepositories {
+		e.track(collectorRemoteRepos, func() error { return e.exportRemoteRepositories(ch) })
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.VirtualRepositories {
+		e.track(collectorVirtualRepos, func() error { return e.exportVirtualRepositories(ch) })
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.Projects {
+		e.track(collectorProjects, func() error { return e.exportProjects(repoSummaryList, ch) })
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.AccessTokens {
+		e.track(collectorAccessTokens, func() error { return e.exportAccessTokens(ch) })
+	}
+
+	if e.exporterRuntimeConfig.OptionalMetrics.GPGKeys {
+		e.track(collectorGPGKeys, func() error { return e.exportGPGKeys(ch) })
 	}
 
 	if e.exporterRuntimeConfig.OptionalMetrics.AccessFederationValidate {
-		e.exportAccessFederationValidate(ch)
+		e.track(collectorAccessFederation, func() error { return e.exportAccessFederationValidate(ch) })
 	}
 
 	return true
 }
 
+// exportCacheStats exports the conditional request counters of the API response cache.
+func (e *Exporter) exportCacheStats(ch chan<- prometheus.Metric) {
+	stats := e.client.CacheStats()
+	for metricName, metric := range cacheMetrics {
+		switch metricName {
+		case "notModified":
+			ch <- prometheus.MustNewConstMetric(metric, prometheus.CounterValue, float64(stats.NotModified))
+		case "savedBytes":
+			ch <- prometheus.MustNewConstMetric(metric, prometheus.CounterValue, float64(stats.BytesSaved))
+		}
+	}
+}
+
 // collectBackgroundTasks emits a count of background tasks by (type, state) combination.
 func (e *Exporter) collectBackgroundTasks() {
 	e.logger.Debug("Collecting background tasks metrics")
 
-	tasks, err := e.client.FetchBackgroundTasks()
+	var tasks []artifactory.BackgroundTask
+	err := e.track(collectorBackgroundTasks, func() error {
+		var err error
+		tasks, err = e.client.FetchBackgroundTasks()
+		return err
+	})
 	if err != nil {
 		e.logger.Error("Error fetching background tasks", "err", err)
 		return
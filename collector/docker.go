@@ -0,0 +1,247 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// maxDockerImageSeries bounds the number of images exported with their own
+// series. Repository totals stay exact.
+const maxDockerImageSeries = 5000
+
+// dockerManifestNames restricts the AQL queries to the manifests of the tag
+// folders.
+const dockerManifestNames = `"$or" : [{"name" : "manifest.json"}, {"name" : "list.manifest.json"}]`
+
+// dockerItem is a manifest of a Docker repository returned by AQL.
+type dockerItem struct {
+	Path    string `json:"path"`
+	Name    string `json:"name"`
+	Created string `json:"created"`
+	Sha1    string `json:"actual_sha1"`
+}
+
+// dockerFolder is a tag folder holding a manifest.
+type dockerFolder struct {
+	// list is set for the list.manifest.json of a multi-arch tag, which
+	// references platform manifests instead of layers.
+	list    bool
+	created time.Time
+	sha1    string
+	layers  map[string]float64
+}
+
+// dockerImage aggregates the tags and distinct layers of an image.
+type dockerImage struct {
+	tags   float64
+	size   float64
+	oldest time.Time
+	newest time.Time
+	layers map[string]bool
+}
+
+// newDockerCollector returns a scheduled collector counting the images and
+// tags of every Docker repository.
+func (e *Exporter) newDockerCollector() *scheduledCollector {
+	return &scheduledCollector{
+		name:     collectorDocker,
+		interval: e.exporterRuntimeConfig.DockerInterval,
+		descs: []*prometheus.Desc{
+			dockerMetrics["images"],
+			dockerMetrics["tags"],
+			dockerMetrics["imageTags"],
+			dockerMetrics["imageSize"],
+			dockerMetrics["oldestTagAge"],
+			dockerMetrics["newestTagAge"],
+		},
+		collect: e.exportDocker,
+	}
+}
+
+// exportDocker exports the images and tags of the local Docker repositories
+// and remote Docker repository caches. Artifactory stores every tag in a
+// <image>/<tag> folder holding its manifest.json (or list.manifest.json), so
+// the images and tags are derived from the paths of the manifests. The size of
+// an image sums the distinct layers referenced by the manifests of its tags,
+// which are downloaded once and kept by checksum until they change.
+func (e *Exporter) exportDocker(ctx context.Context, ch chan<- prometheus.Metric) error {
+	repositories, nodeId, err := e.fetchItemRepositories()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	imageSeries := 0
+	layers := map[string]map[string]float64{}
+
+	for _, repo := range repositories {
+		if repo.packageType != "docker" {
+			continue
+		}
+		e.logger.Debug(
+			"Counting Docker images",
+			"repo", repo.key,
+		)
+		folders := map[string]*dockerFolder{}
+		criteria := fmt.Sprintf("{\"repo\" : %q, \"type\" : \"file\", %s}", repo.key, dockerManifestNames)
+		_, err := e.queryItemsPaged(ctx, criteria, `"created", "actual_sha1"`, func(dec *json.Decoder) error {
+			var item dockerItem
+			if err := dec.Decode(&item); err != nil {
+				return err
+			}
+			addDockerItem(folders, item)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		e.fetchDockerLayers(ctx, repo.key, folders, layers)
+		images := dockerImages(folders)
+
+		var tags float64
+		for _, image := range images {
+			tags += image.tags
+		}
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "docker",
+			"repo", repo.key,
+			"images", len(images),
+			"tags", tags,
+		)
+		ch <- prometheus.MustNewConstMetric(dockerMetrics["images"], prometheus.GaugeValue, float64(len(images)), repo.key, repo.rclass, nodeId)
+		ch <- prometheus.MustNewConstMetric(dockerMetrics["tags"], prometheus.GaugeValue, tags, repo.key, repo.rclass, nodeId)
+
+		names := make([]string, 0, len(images))
+		for name := range images {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if imageSeries >= maxDockerImageSeries {
+				e.logger.Warn(
+					"Too many Docker images, skipping the per image metrics of the remaining ones",
+					"limit", maxDockerImageSeries,
+					"repo", repo.key,
+				)
+				break
+			}
+			imageSeries++
+			image := images[name]
+			ch <- prometheus.MustNewConstMetric(dockerMetrics["imageTags"], prometheus.GaugeValue, image.tags, repo.key, name, nodeId)
+			ch <- prometheus.MustNewConstMetric(dockerMetrics["imageSize"], prometheus.GaugeValue, image.size, repo.key, name, nodeId)
+			ch <- prometheus.MustNewConstMetric(dockerMetrics["oldestTagAge"], prometheus.GaugeValue, now.Sub(image.oldest).Seconds(), repo.key, name, nodeId)
+			ch <- prometheus.MustNewConstMetric(dockerMetrics["newestTagAge"], prometheus.GaugeValue, now.Sub(image.newest).Seconds(), repo.key, name, nodeId)
+		}
+	}
+	// Only the manifests still present are kept
+	e.dockerLayers = layers
+	return nil
+}
+
+// addDockerItem records a manifest of a Docker repository as its folder.
+// Other files are skipped, in case the query returned some.
+func addDockerItem(folders map[string]*dockerFolder, item dockerItem) {
+	if item.Name != "manifest.json" && item.Name != "list.manifest.json" {
+		return
+	}
+	folder, ok := folders[item.Path]
+	if !ok {
+		folder = &dockerFolder{}
+		folders[item.Path] = folder
+	}
+	if item.Name == "manifest.json" {
+		folder.sha1 = item.Sha1
+	}
+	// The layers come from the manifest.json if a folder holds both
+	folder.list = folder.sha1 == "" && item.Name == "list.manifest.json"
+	if created, err := time.Parse(time.RFC3339, item.Created); err == nil {
+		folder.created = created
+	}
+}
+
+// fetchDockerLayers sets the layers referenced by the manifest.json of every
+// folder. Manifests are downloaded only when their checksum is not known from
+// the previous run, the layers of the manifests seen are kept in layers for
+// the next one. Folders whose manifest cannot be downloaded have no layers.
+func (e *Exporter) fetchDockerLayers(ctx context.Context, repoKey string, folders map[string]*dockerFolder, layers map[string]map[string]float64) {
+	var missing []string
+	for folderPath, folder := range folders {
+		if folder.list {
+			continue
+		}
+		if known, ok := e.dockerLayers[folder.sha1]; ok && folder.sha1 != "" {
+			folder.layers = known
+			layers[folder.sha1] = known
+			continue
+		}
+		missing = append(missing, folderPath)
+	}
+	sort.Strings(missing)
+	manifests := fetchEach(ctx, e, collectorDocker, missing,
+		func(folderPath string) string { return repoKey + "/" + folderPath },
+		func(folderPath string) (artifactory.DockerManifest, error) {
+			return e.client.FetchDockerManifest(repoKey, folderPath)
+		},
+	)
+	for _, manifest := range manifests {
+		folderLayers := make(map[string]float64, len(manifest.value.Layers))
+		for _, layer := range manifest.value.Layers {
+			folderLayers[layer.Digest] = layer.Size
+		}
+		folder := folders[manifest.item]
+		folder.layers = folderLayers
+		if folder.sha1 != "" {
+			layers[folder.sha1] = folderLayers
+		}
+	}
+}
+
+// dockerImages groups the folders holding a manifest by image. The
+// sha256:<digest> folders of the platform manifests of a multi-arch tag
+// contribute their layers to the image but are not counted as tags.
+func dockerImages(folders map[string]*dockerFolder) map[string]*dockerImage {
+	images := map[string]*dockerImage{}
+	for folderPath, folder := range folders {
+		name, tag := path.Dir(folderPath), path.Base(folderPath)
+		if name == "." {
+			continue
+		}
+		image, ok := images[name]
+		if !ok {
+			image = &dockerImage{layers: map[string]bool{}}
+			images[name] = image
+		}
+		for layer, size := range folder.layers {
+			if !image.layers[layer] {
+				image.layers[layer] = true
+				image.size += size
+			}
+		}
+		if strings.HasPrefix(tag, "sha256:") {
+			continue
+		}
+		image.tags++
+		if image.oldest.IsZero() || folder.created.Before(image.oldest) {
+			image.oldest = folder.created
+		}
+		if folder.created.After(image.newest) {
+			image.newest = folder.created
+		}
+	}
+	// Images only made of platform manifests have no tag to report
+	for name, image := range images {
+		if image.tags == 0 {
+			delete(images, name)
+		}
+	}
+	return images
+}
@@ -0,0 +1,116 @@
+package collector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestDockerImages(t *testing.T) {
+	folders := map[string]*dockerFolder{}
+	for _, item := range []dockerItem{
+		{Path: "app/1.0", Name: "manifest.json", Created: "2024-01-01T00:00:00.000Z", Sha1: "1"},
+		{Path: "app/2.0", Name: "manifest.json", Created: "2024-03-01T00:00:00.000Z", Sha1: "2"},
+		// Multi-arch tag with its platform manifest
+		{Path: "team/tool/latest", Name: "list.manifest.json", Created: "2024-02-01T00:00:00.000Z"},
+		{Path: "team/tool/sha256:111", Name: "manifest.json", Created: "2024-02-01T00:00:00.000Z", Sha1: "3"},
+		// Upload leftovers and unrelated files
+		{Path: "app/_uploads", Name: "sha256__eee"},
+		{Path: ".", Name: "repository.catalog"},
+	} {
+		addDockerItem(folders, item)
+	}
+	if len(folders) != 4 || !folders["team/tool/latest"].list {
+		t.Fatalf("Unexpected folders %v", folders)
+	}
+	folders["app/1.0"].layers = map[string]float64{"sha256:aaa": 100, "sha256:bbb": 10}
+	folders["app/2.0"].layers = map[string]float64{"sha256:aaa": 100, "sha256:ccc": 20}
+	folders["team/tool/sha256:111"].layers = map[string]float64{"sha256:ddd": 50}
+
+	images := dockerImages(folders)
+	if len(images) != 2 {
+		t.Fatalf("images = %d, want 2", len(images))
+	}
+	app := images["app"]
+	if app.tags != 2 || app.size != 130 {
+		t.Errorf("app tags = %v, size = %v, want 2, 130", app.tags, app.size)
+	}
+	if !app.oldest.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) || !app.newest.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("app oldest = %v, newest = %v", app.oldest, app.newest)
+	}
+	tool := images["team/tool"]
+	if tool.tags != 1 || tool.size != 50 {
+		t.Errorf("team/tool tags = %v, size = %v, want 1, 50", tool.tags, tool.size)
+	}
+}
+
+func TestExportDockerSumsManifestLayers(t *testing.T) {
+	var downloads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/repositories":
+			w.Write([]byte(`[
+				{"key":"docker-local","type":"LOCAL","packageType":"Docker"},
+				{"key":"libs-release","type":"LOCAL","packageType":"Maven"}
+			]`))
+		case "/api/search/aql":
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), `"repo" : "docker-local"`) || !strings.Contains(string(body), dockerManifestNames) {
+				t.Errorf("Expected query filtered on manifests of docker-local, got %s", body)
+			}
+			w.Write([]byte(`{"results":[
+				{"repo":"docker-local","path":"app/1.0","name":"manifest.json","created":"2024-01-01T00:00:00.000Z","actual_sha1":"1"},
+				{"repo":"docker-local","path":"app/2.0","name":"manifest.json","created":"2024-02-01T00:00:00.000Z","actual_sha1":"2"},
+				{"repo":"docker-local","path":"app/3.0","name":"manifest.json","created":"2024-03-01T00:00:00.000Z","actual_sha1":"3"}
+			]}`))
+		case "/docker-local/app/1.0/manifest.json":
+			downloads = append(downloads, r.URL.Path)
+			w.Write([]byte(`{"schemaVersion":2,"config":{"digest":"sha256:cfg","size":5},"layers":[{"digest":"sha256:aaa","size":100},{"digest":"sha256:bbb","size":10}]}`))
+		case "/docker-local/app/2.0/manifest.json":
+			downloads = append(downloads, r.URL.Path)
+			w.Write([]byte(`{"schemaVersion":2,"layers":[{"digest":"sha256:aaa","size":100}]}`))
+		default:
+			downloads = append(downloads, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+	e.exporterRuntimeConfig.ArtifactsAQLPageSize = 100
+
+	for run := 0; run < 2; run++ {
+		ch := make(chan prometheus.Metric, 20)
+		if err := e.track(collectorDocker, func() error { return e.exportDocker(context.Background(), ch) }); err != nil {
+			t.Fatalf("exportDocker() error = %v", err)
+		}
+		close(ch)
+		for m := range ch {
+			if m.Desc() != dockerMetrics["imageSize"] {
+				continue
+			}
+			var metric dto.Metric
+			if err := m.Write(&metric); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			// The layers referenced by the manifests, the config blob is not a layer
+			if got := metric.GetGauge().GetValue(); got != 110 {
+				t.Errorf("Image size = %v, want 110", got)
+			}
+		}
+		if skipped := e.Status().Collectors[collectorDocker].LastSkipped; skipped != 1 {
+			t.Errorf("LastSkipped = %d, want 1 for the missing manifest", skipped)
+		}
+	}
+	// Known manifests are not downloaded again, the missing one is retried
+	if len(downloads) != 4 {
+		t.Errorf("Expected 3 downloads, then 1 retry, got %v", downloads)
+	}
+}
@@ -0,0 +1,125 @@
+package collector
+
+import (
+	"sync"
+	"time"
+)
+
+// maxTrackedDownloads bounds the number of items whose download counters are
+// kept in memory.
+const maxTrackedDownloads = 100000
+
+type downloadObservation struct {
+	at        time.Time
+	downloads float64
+}
+
+// downloadTracker remembers the download counters (stat.downloads) of
+// recently downloaded items to estimate the number of downloads within a time
+// interval from their deltas. Items downloaded within the longest time
+// interval are kept with an observation each time their counter changed, and
+// items not downloaded anymore with their last counter while there is room,
+// for at most limit items: items without history are assumed downloaded once.
+type downloadTracker struct {
+	mutex sync.Mutex
+	limit int
+	// map[<repo>]map[<path>/<name>] observations
+	items map[string]map[string][]downloadObservation
+}
+
+func newDownloadTracker(limit int) *downloadTracker {
+	return &downloadTracker{
+		limit: limit,
+		items: map[string]map[string][]downloadObservation{},
+	}
+}
+
+// tracks reports whether the download counter of an item is kept.
+func (t *downloadTracker) tracks(repo string, item string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return len(t.items[repo][item]) > 0
+}
+
+// estimate returns the number of downloads of an item downloaded within
+// period, given its current download counter. The counter observed at the
+// start of the period is subtracted when known. Otherwise the item is assumed
+// to have been downloaded once before it was first observed.
+func (t *downloadTracker) estimate(repo string, item string, downloads float64, now time.Time, period time.Duration) float64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	observations := t.items[repo][item]
+	if len(observations) == 0 {
+		return 1
+	}
+	since := now.Add(-period)
+	base := observations[0].downloads - 1
+	for _, o := range observations {
+		if o.at.After(since) {
+			break
+		}
+		base = o.downloads
+	}
+	// The item was downloaded within the period, so at least once
+	return max(downloads-base, 1)
+}
+
+// record replaces the tracked items by the current download counters of the
+// items of every repository downloaded within retention. Observations older
+// than retention are pruned, except the latest of them which holds the
+// counter at the start of the retention. Items already tracked are kept
+// first, new items only while there is room for them. Items of the
+// repositories of current not downloaded within retention anymore keep their
+// last counter in the remaining room, so that their next downloads are not
+// counted as one; repositories missing from current are forgotten. It returns
+// the number of downloaded items left out.
+func (t *downloadTracker) record(current map[string]map[string]float64, now time.Time, retention time.Duration) int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	since := now.Add(-retention)
+	items := make(map[string]map[string][]downloadObservation, len(current))
+	tracked, untracked := 0, 0
+	for _, known := range []bool{true, false} {
+		for repo, downloaded := range current {
+			for item, downloads := range downloaded {
+				observations := t.items[repo][item]
+				if (len(observations) > 0) != known {
+					continue
+				}
+				if tracked >= t.limit {
+					untracked++
+					continue
+				}
+				if len(observations) == 0 || observations[len(observations)-1].downloads != downloads {
+					observations = append(observations, downloadObservation{at: now, downloads: downloads})
+				}
+				first := 0
+				for first < len(observations)-1 && !observations[first+1].at.After(since) {
+					first++
+				}
+				if items[repo] == nil {
+					items[repo] = map[string][]downloadObservation{}
+				}
+				items[repo][item] = observations[first:]
+				tracked++
+			}
+		}
+	}
+	for repo, downloaded := range current {
+		for item, observations := range t.items[repo] {
+			if tracked >= t.limit {
+				break
+			}
+			if _, exists := downloaded[item]; exists {
+				continue
+			}
+			if items[repo] == nil {
+				items[repo] = map[string][]downloadObservation{}
+			}
+			items[repo][item] = observations[len(observations)-1:]
+			tracked++
+		}
+	}
+	t.items = items
+	return untracked
+}
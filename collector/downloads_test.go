@@ -0,0 +1,94 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDownloadTracker(t *testing.T) {
+	tracker := newDownloadTracker(maxTrackedDownloads)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	const repo, item = "libs-release", "org/lib/1.0/lib-1.0.jar"
+
+	if got := tracker.estimate(repo, item, 10, start, 5*time.Minute); got != 1 {
+		t.Errorf("estimate() for unknown item = %v, want 1", got)
+	}
+	tracker.record(map[string]map[string]float64{repo: {item: 10}}, start, 15*time.Minute)
+
+	// First observed within the period: one download assumed before it
+	now := start.Add(time.Minute)
+	if got := tracker.estimate(repo, item, 13, now, 5*time.Minute); got != 4 {
+		t.Errorf("estimate() within first period = %v, want 4", got)
+	}
+	tracker.record(map[string]map[string]float64{repo: {item: 13}}, now, 15*time.Minute)
+
+	// Counter at the start of the period is known
+	now = start.Add(10 * time.Minute)
+	if got := tracker.estimate(repo, item, 20, now, 5*time.Minute); got != 7 {
+		t.Errorf("estimate() with known start = %v, want 7", got)
+	}
+	if got := tracker.estimate(repo, item, 20, now, 15*time.Minute); got != 11 {
+		t.Errorf("estimate() for longest period = %v, want 11", got)
+	}
+	// Downloaded within the period, so at least once
+	if got := tracker.estimate(repo, item, 13, now, 5*time.Minute); got != 1 {
+		t.Errorf("estimate() without counter change = %v, want 1", got)
+	}
+
+	// Observations older than the retention are pruned except the latest
+	now = start.Add(time.Hour)
+	tracker.record(map[string]map[string]float64{repo: {item: 20}}, now, 15*time.Minute)
+	if n := len(tracker.items[repo][item]); n != 2 {
+		t.Errorf("Expected 2 observations after pruning, got %d", n)
+	}
+
+	// Items not downloaded anymore keep their last counter
+	tracker.record(map[string]map[string]float64{repo: {}}, now, 15*time.Minute)
+	if n := len(tracker.items[repo][item]); n != 1 {
+		t.Fatalf("Expected the last observation to be kept, got %d", n)
+	}
+	now = start.Add(2 * time.Hour)
+	if got := tracker.estimate(repo, item, 23, now, 5*time.Minute); got != 3 {
+		t.Errorf("estimate() after the item left the window = %v, want 3", got)
+	}
+}
+
+func TestDownloadTrackerLimit(t *testing.T) {
+	tracker := newDownloadTracker(2)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	untracked := tracker.record(map[string]map[string]float64{
+		"libs-release": {"a.jar": 1},
+		"deleted-repo": {"b.jar": 1},
+	}, now, 15*time.Minute)
+	if untracked != 0 {
+		t.Errorf("record() left out %d items, want 0", untracked)
+	}
+
+	// Repositories gone are pruned, known items are kept before new ones
+	now = now.Add(time.Minute)
+	untracked = tracker.record(map[string]map[string]float64{
+		"libs-release": {"a.jar": 2, "c.jar": 1, "d.jar": 1},
+	}, now, 15*time.Minute)
+	if untracked != 1 {
+		t.Errorf("record() left out %d items, want 1", untracked)
+	}
+	if _, ok := tracker.items["deleted-repo"]; ok {
+		t.Error("Expected the items of a deleted repository to be forgotten")
+	}
+	if !tracker.tracks("libs-release", "a.jar") {
+		t.Error("Expected the already tracked item to be kept")
+	}
+	if n := len(tracker.items["libs-release"]); n != 2 {
+		t.Errorf("Expected 2 tracked items, got %d", n)
+	}
+
+	// Items not downloaded anymore only fill the room left
+	now = now.Add(time.Minute)
+	tracker.record(map[string]map[string]float64{
+		"libs-release": {"e.jar": 1},
+	}, now, 15*time.Minute)
+	if !tracker.tracks("libs-release", "e.jar") || len(tracker.items["libs-release"]) != 2 {
+		t.Errorf("Expected the downloaded item and one item not downloaded anymore, got %v", tracker.items["libs-release"])
+	}
+}
@@ -1,6 +1,7 @@
 package collector
 
 import (
+	"context"
 	"log/slog"
 	"sync"
 
@@ -21,6 +22,16 @@ type Exporter struct {
 	totalScrapes, totalAPIErrors, jsonParseFailures prometheus.Counter
 	logger                                          *slog.Logger
 	backgroundTaskMetrics                           *prometheus.GaugeVec
+	status                                          *statusTracker
+	storageHistory                                  *storageHistory
+	downloads                                       *downloadTracker
+	topArtifacts                                    topArtifactsStore
+	repoConfigs                                     repoConfigStore
+	dockerLayers                                    map[string]map[string]float64
+
+	scheduled     []*scheduledCollector
+	stopScheduled context.CancelFunc
+	scheduledRuns sync.WaitGroup
 }
 
 // NewExporter returns an initialized Exporter.
@@ -36,7 +47,12 @@ func NewExporter(conf *config.Config) (*Exporter, error) {
 		[]string{"type", "state"},
 	)
 
-	return &Exporter{
+	var history *storageHistory
+	if conf.ExporterRuntimeConfig.StorageGrowthWindow > 0 {
+		history = newStorageHistory(conf.ExporterRuntimeConfig.StorageGrowthWindow)
+	}
+
+	e := &Exporter{
 		client:                client,
 		exporterRuntimeConfig: *conf.ExporterRuntimeConfig,
 		up: prometheus.NewGauge(prometheus.GaugeOpts{
@@ -61,5 +77,54 @@ func NewExporter(conf *config.Config) (*Exporter, error) {
 		}),
 		logger:                conf.Logger,
 		backgroundTaskMetrics: backgroundTaskMetrics,
-	}, nil
+		status:                newStatusTracker(conf.ReadyCheckInterval),
+		storageHistory:        history,
+		downloads:             newDownloadTracker(maxTrackedDownloads),
+	}
+	if conf.ExporterRuntimeConfig.OptionalMetrics.Repositories || conf.ExporterRuntimeConfig.OptionalMetrics.RemoteRepositories || conf.ExporterRuntimeConfig.OptionalMetrics.VirtualRepositories {
+		e.scheduled = append(e.scheduled, e.newRepoConfigsCollector())
+	}
+	if conf.ExporterRuntimeConfig.OptionalMetrics.RemoteRepositories && conf.ExporterRuntimeConfig.RemoteRepoURLCheck {
+		e.scheduled = append(e.scheduled, e.newUpstreamProbesCollector())
+	}
+	if conf.ExporterRuntimeConfig.OptionalMetrics.StaleArtifacts {
+		e.scheduled = append(e.scheduled, e.newStaleArtifactsCollector())
+	}
+	if conf.ExporterRuntimeConfig.OptionalMetrics.TopArtifacts {
+		e.scheduled = append(e.scheduled, e.newTopArtifactsCollector())
+	}
+	if conf.ExporterRuntimeConfig.OptionalMetrics.ArtifactSize {
+		e.scheduled = append(e.scheduled, e.newArtifactSizeCollector())
+	}
+	if conf.ExporterRuntimeConfig.OptionalMetrics.Docker {
+		e.scheduled = append(e.scheduled, e.newDockerCollector())
+	}
+	if conf.ExporterRuntimeConfig.OptionalMetrics.Builds {
+		e.scheduled = append(e.scheduled, e.newBuildsCollector())
+	}
+	if conf.ExporterRuntimeConfig.OptionalMetrics.PermissionTargets {
+		e.scheduled = append(e.scheduled, e.newPermissionTargetsCollector())
+	}
+	if conf.ExporterRuntimeConfig.OptionalMetrics.UserDetails {
+		e.scheduled = append(e.scheduled, e.newUserDetailsCollector())
+	}
+	if conf.ExporterRuntimeConfig.OptionalMetrics.GroupDetails {
+		e.scheduled = append(e.scheduled, e.newGroupDetailsCollector())
+	}
+	for _, query := range conf.ExporterRuntimeConfig.AQLQueries {
+		e.scheduled = append(e.scheduled, e.newAQLQueryCollector(query))
+	}
+	e.startScheduled()
+	return e, nil
+}
+
+// Close releases the resources held by the exporter, including the
+// scheduled collectors and the background goroutines of its Artifactory client.
+// The requests in flight are aborted, so it does not wait for running collections.
+func (e *Exporter) Close() {
+	if e.stopScheduled != nil {
+		e.stopScheduled()
+	}
+	e.client.Close()
+	e.scheduledRuns.Wait()
 }
@@ -0,0 +1,64 @@
+package collector
+
+import (
+	"context"
+	"sync"
+)
+
+// maxConcurrentFetches bounds the number of per item API calls made at once by fetchEach.
+const maxConcurrentFetches = 8
+
+// fetched is an item with the details fetched for it.
+type fetched[T, R any] struct {
+	item  T
+	value R
+}
+
+// fetchEach fetches the details of every item with at most maxConcurrentFetches
+// API calls at once. Items whose fetch fails, e.g. deleted since they were
+// listed, are logged, counted as API errors and as skipped items of the
+// collector, and left out of the results. The results keep the
+// order of the items. No fetch is started once ctx is done, the results are
+// then incomplete and nothing is logged nor counted.
+func fetchEach[T, R any](ctx context.Context, e *Exporter, collector string, items []T, name func(T) string, fetch func(T) (R, error)) []fetched[T, R] {
+	values := make([]R, len(items))
+	errs := make([]error, len(items))
+	sem := make(chan struct{}, maxConcurrentFetches)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		go func(i int, item T) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			values[i], errs[i] = fetch(item)
+		}(i, item)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	results := make([]fetched[T, R], 0, len(items))
+	skipped := 0
+	for i, item := range items {
+		if errs[i] != nil {
+			e.logger.Warn(
+				"Couldn't fetch details, skipping",
+				"collector", collector,
+				"item", name(item),
+				"err", errs[i].Error(),
+			)
+			e.totalAPIErrors.Inc()
+			skipped++
+			continue
+		}
+		results = append(results, fetched[T, R]{item: item, value: values[i]})
+	}
+	e.status.skip(collector, skipped)
+	return results
+}
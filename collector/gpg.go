@@ -0,0 +1,119 @@
+package collector
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gpgKey is a GPG public key or subkey with its expiry, zero for keys which
+// never expire.
+type gpgKey struct {
+	keyId  string
+	expiry time.Time
+}
+
+// gpgKeyExpiry returns the expiry of a key created at creation with the given
+// lifetime, zero if it never expires.
+func gpgKeyExpiry(creation time.Time, lifetimeSecs *uint32) time.Time {
+	if lifetimeSecs == nil || *lifetimeSecs == 0 {
+		return time.Time{}
+	}
+	return creation.Add(time.Duration(*lifetimeSecs) * time.Second)
+}
+
+// parseGPGKeys parses the expiry of the keys of an ASCII armored key ring and
+// of their subkeys which are not revoked. The expiry of a primary key is given
+// by the self-signature of its primary identity, the expiry of a subkey by its
+// binding signature, so an expiring signing subkey of a primary key which
+// never expires is reported too.
+func parseGPGKeys(armored string) ([]gpgKey, error) {
+	entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var keys []gpgKey
+	for _, entity := range entities {
+		key := gpgKey{keyId: entity.PrimaryKey.KeyIdString()}
+		if identity := entity.PrimaryIdentity(); identity != nil {
+			key.expiry = gpgKeyExpiry(entity.PrimaryKey.CreationTime, identity.SelfSignature.KeyLifetimeSecs)
+		}
+		keys = append(keys, key)
+		for _, subkey := range entity.Subkeys {
+			if subkey.Sig == nil || subkey.Revoked(now) {
+				continue
+			}
+			keys = append(keys, gpgKey{
+				keyId:  subkey.PublicKey.KeyIdString(),
+				expiry: gpgKeyExpiry(subkey.PublicKey.CreationTime, subkey.Sig.KeyLifetimeSecs),
+			})
+		}
+	}
+	return keys, nil
+}
+
+// exportGPGKeys exports the seconds to expiration of the GPG signing key and of
+// the trusted keys and of their subkeys, parsed from the public keys. Keys
+// which never expire are exported with +Inf. Keys which cannot be parsed are
+// skipped and counted in the collector status.
+func (e *Exporter) exportGPGKeys(ch chan<- prometheus.Metric) error {
+	keys, err := e.client.FetchGPGKeys()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching GPG keys",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return err
+	}
+
+	type armoredKey struct {
+		keyType string
+		alias   string
+		key     string
+	}
+	var armoredKeys []armoredKey
+	if keys.SigningKey != "" {
+		armoredKeys = append(armoredKeys, armoredKey{keyType: "signing", key: keys.SigningKey})
+	}
+	for _, trusted := range keys.TrustedKeys {
+		armoredKeys = append(armoredKeys, armoredKey{keyType: "trusted", alias: trusted.Alias, key: trusted.Key})
+	}
+
+	timeNow := time.Now()
+	for _, armored := range armoredKeys {
+		parsed, err := parseGPGKeys(armored.key)
+		if err != nil {
+			e.logger.Warn(
+				"Couldn't parse GPG key",
+				"type", armored.keyType,
+				"alias", armored.alias,
+				"err", err.Error(),
+			)
+			e.status.skip(collectorGPGKeys, 1)
+			continue
+		}
+		for _, key := range parsed {
+			validThrough := math.Inf(1)
+			expires := "never"
+			if !key.expiry.IsZero() {
+				validThrough = key.expiry.Sub(timeNow).Seconds()
+				expires = key.expiry.UTC().Format(time.RFC3339)
+			}
+			e.logger.Debug(
+				"Registering metric",
+				"metric", "gpgKeys",
+				"type", armored.keyType,
+				"key_id", key.keyId,
+				"alias", armored.alias,
+				"expires", expires,
+			)
+			ch <- prometheus.MustNewConstMetric(gpgKeyMetrics["keys"], prometheus.GaugeValue, validThrough, armored.keyType, key.keyId, armored.alias, expires, keys.NodeId)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// testKeyConfig returns the configuration of an ed25519 key expiring after
+// lifetime seconds, never if zero.
+func testKeyConfig(lifetime uint32) *packet.Config {
+	return &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA, KeyLifetimeSecs: lifetime}
+}
+
+// armoredTestKey returns the ASCII armored public key of entity.
+func armoredTestKey(t *testing.T, entity *openpgp.Entity) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("armor.Encode() error = %v", err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatalf("Serialize() error = %v", err)
+	}
+	w.Close()
+	return buf.String()
+}
+
+// newTestKey returns a new ed25519 key with an encryption subkey, both
+// expiring after lifetime seconds, never if zero.
+func newTestKey(t *testing.T, lifetime uint32) *openpgp.Entity {
+	t.Helper()
+	entity, err := openpgp.NewEntity("Test", "", "test@example.com", testKeyConfig(lifetime))
+	if err != nil {
+		t.Fatalf("NewEntity() error = %v", err)
+	}
+	return entity
+}
+
+func TestParseGPGKeys(t *testing.T) {
+	entity := newTestKey(t, 30*24*3600)
+	keys, err := parseGPGKeys(armoredTestKey(t, entity))
+	if err != nil {
+		t.Fatalf("parseGPGKeys() error = %v", err)
+	}
+	if len(keys) != 2 || keys[0].keyId != entity.PrimaryKey.KeyIdString() {
+		t.Fatalf("Unexpected keys: %+v", keys)
+	}
+	// Serialized keys have a creation time in seconds
+	want := entity.PrimaryKey.CreationTime.Truncate(time.Second).Add(30 * 24 * time.Hour)
+	if !keys[0].expiry.Equal(want) {
+		t.Errorf("expiry = %v, want %v", keys[0].expiry, want)
+	}
+
+	keys, err = parseGPGKeys(armoredTestKey(t, newTestKey(t, 0)))
+	if err != nil {
+		t.Fatalf("parseGPGKeys() error = %v", err)
+	}
+	if len(keys) != 2 || !keys[0].expiry.IsZero() || !keys[1].expiry.IsZero() {
+		t.Errorf("Expected keys without expiry, got %+v", keys)
+	}
+
+	if _, err := parseGPGKeys("not a key"); err == nil {
+		t.Error("Expected an error for an invalid key")
+	}
+}
+
+func TestParseGPGKeysSigningSubkey(t *testing.T) {
+	// Primary key which never expires with an expiring signing subkey, as
+	// commonly used to sign Debian and RPM repositories
+	entity := newTestKey(t, 0)
+	if err := entity.AddSigningSubkey(testKeyConfig(30 * 24 * 3600)); err != nil {
+		t.Fatalf("AddSigningSubkey() error = %v", err)
+	}
+	subkey := entity.Subkeys[len(entity.Subkeys)-1].PublicKey
+
+	keys, err := parseGPGKeys(armoredTestKey(t, entity))
+	if err != nil {
+		t.Fatalf("parseGPGKeys() error = %v", err)
+	}
+	expiries := map[string]time.Time{}
+	for _, key := range keys {
+		expiries[key.keyId] = key.expiry
+	}
+	if len(expiries) != 3 {
+		t.Fatalf("Expected the primary key and 2 subkeys, got %+v", keys)
+	}
+	if expiry := expiries[entity.PrimaryKey.KeyIdString()]; !expiry.IsZero() {
+		t.Errorf("primary key expiry = %v, want never", expiry)
+	}
+	want := subkey.CreationTime.Truncate(time.Second).Add(30 * 24 * time.Hour)
+	if expiry := expiries[subkey.KeyIdString()]; !expiry.Equal(want) {
+		t.Errorf("signing subkey expiry = %v, want %v", expiry, want)
+	}
+}
+
+func TestExportGPGKeysSkipsInvalidKeys(t *testing.T) {
+	entity := newTestKey(t, 0)
+	trusted, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kid": "1", "alias": "debian", "key": armoredTestKey(t, entity)},
+			{"kid": "2", "alias": "broken", "key": "not a key"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/security/keys/trusted":
+			w.Write(trusted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+
+	ch := make(chan prometheus.Metric, 10)
+	if err := e.track(collectorGPGKeys, func() error { return e.exportGPGKeys(ch) }); err != nil {
+		t.Fatalf("exportGPGKeys() error = %v", err)
+	}
+	close(ch)
+	values := map[string]float64{}
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		values[labelValue(&metric, "key_id")] = metric.GetGauge().GetValue()
+		if alias := labelValue(&metric, "alias"); alias != "debian" {
+			t.Errorf("alias = %q, want debian", alias)
+		}
+	}
+	if len(values) != 2 || !math.IsInf(values[entity.PrimaryKey.KeyIdString()], 1) {
+		t.Errorf("Unexpected keys: %v", values)
+	}
+	if skipped := e.Status().Collectors[collectorGPGKeys].LastSkipped; skipped != 1 {
+		t.Errorf("LastSkipped = %d, want 1", skipped)
+	}
+}
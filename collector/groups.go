@@ -0,0 +1,96 @@
+package collector
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// maxGroupDetailsSeries bounds the number of groups exported with their own
+// series. The empty and admin group counts stay exact.
+const maxGroupDetailsSeries = 1000
+
+// newGroupDetailsCollector returns a scheduled collector fetching the details
+// of every group.
+func (e *Exporter) newGroupDetailsCollector() *scheduledCollector {
+	return &scheduledCollector{
+		name:     collectorGroupDetails,
+		interval: e.exporterRuntimeConfig.GroupDetailsInterval,
+		descs: []*prometheus.Desc{
+			groupDetailsMetrics["info"],
+			groupDetailsMetrics["members"],
+			groupDetailsMetrics["emptyGroups"],
+			groupDetailsMetrics["adminGroups"],
+		},
+		collect: e.exportGroupDetails,
+	}
+}
+
+// exportGroupDetails exports the realm, auto-join, admin privileges and
+// number of members of each group, whose details take one API call each.
+func (e *Exporter) exportGroupDetails(ctx context.Context, ch chan<- prometheus.Metric) error {
+	groups, err := e.client.FetchGroups()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching security/groups",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return err
+	}
+
+	fetchedDetails := fetchEach(ctx, e, collectorGroupDetails, groups.Groups,
+		func(group artifactory.Group) string { return group.Name },
+		func(group artifactory.Group) (artifactory.GroupDetails, error) {
+			return e.client.FetchGroupDetails(group.Name)
+		},
+	)
+	details := make([]artifactory.GroupDetails, 0, len(fetchedDetails))
+	for _, groupDetails := range fetchedDetails {
+		details = append(details, groupDetails.value)
+	}
+
+	var emptyGroups, adminGroups float64
+	for _, group := range details {
+		if len(group.UserNames) == 0 {
+			emptyGroups++
+		}
+		if group.AdminPrivileges {
+			adminGroups++
+		}
+	}
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "groupDetails",
+		"groups", len(details),
+		"emptyGroups", emptyGroups,
+		"adminGroups", adminGroups,
+	)
+	ch <- prometheus.MustNewConstMetric(groupDetailsMetrics["emptyGroups"], prometheus.GaugeValue, emptyGroups, groups.NodeId)
+	ch <- prometheus.MustNewConstMetric(groupDetailsMetrics["adminGroups"], prometheus.GaugeValue, adminGroups, groups.NodeId)
+
+	if len(details) > maxGroupDetailsSeries {
+		e.logger.Warn(
+			"Too many groups, skipping the per group metrics of the remaining ones",
+			"limit", maxGroupDetailsSeries,
+			"groups", len(details),
+		)
+		// Keep the groups granting admin privileges first
+		sort.Slice(details, func(i, j int) bool {
+			if details[i].AdminPrivileges != details[j].AdminPrivileges {
+				return details[i].AdminPrivileges
+			}
+			return details[i].Name < details[j].Name
+		})
+		details = details[:maxGroupDetailsSeries]
+	}
+	for _, group := range details {
+		ch <- prometheus.MustNewConstMetric(groupDetailsMetrics["info"], prometheus.GaugeValue, 1, group.Name, group.Realm, strconv.FormatBool(group.AutoJoin), strconv.FormatBool(group.AdminPrivileges), groups.NodeId)
+		ch <- prometheus.MustNewConstMetric(groupDetailsMetrics["members"], prometheus.GaugeValue, float64(len(group.UserNames)), group.Name, group.Realm, groups.NodeId)
+	}
+	return nil
+}
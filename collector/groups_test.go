@@ -0,0 +1,72 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestExportGroupDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/security/groups":
+			w.Write([]byte(`[{"name":"readers","uri":"http://localhost/api/security/groups/readers"},{"name":"admins","uri":"http://localhost/api/security/groups/admins"},{"name":"removed","uri":"http://localhost/api/security/groups/removed"}]`))
+		case "/api/security/groups/readers":
+			if r.URL.Query().Get("includeUsers") != "true" {
+				t.Errorf("Expected includeUsers=true, got %q", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"name":"readers","realm":"ldap","autoJoin":true,"adminPrivileges":false,"userNames":[]}`))
+		case "/api/security/groups/admins":
+			w.Write([]byte(`{"name":"admins","realm":"internal","autoJoin":false,"adminPrivileges":true,"userNames":["alice","bob"]}`))
+		default:
+			// removed was deleted since the groups were listed
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+
+	ch := make(chan prometheus.Metric, 10)
+	if err := e.track(collectorGroupDetails, func() error { return e.exportGroupDetails(context.Background(), ch) }); err != nil {
+		t.Fatalf("exportGroupDetails() error = %v", err)
+	}
+	close(ch)
+	values := map[string]float64{}
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		key := ""
+		for metricName, desc := range groupDetailsMetrics {
+			if m.Desc() == desc {
+				key = metricName
+			}
+		}
+		for _, label := range metric.GetLabel() {
+			if label.GetName() != "node_id" {
+				key += "," + label.GetName() + "=" + label.GetValue()
+			}
+		}
+		values[key] = metric.GetGauge().GetValue()
+	}
+	want := map[string]float64{
+		"emptyGroups":                        1,
+		"adminGroups":                        1,
+		"members,name=admins,realm=internal": 2,
+		"info,admin_privileges=false,auto_join=true,name=readers,realm=ldap": 1,
+	}
+	for key, value := range want {
+		if got, ok := values[key]; !ok || got != value {
+			t.Errorf("%s = %v, want %v", key, got, value)
+		}
+	}
+	if skipped := e.Status().Collectors[collectorGroupDetails].LastSkipped; skipped != 1 {
+		t.Errorf("LastSkipped = %d, want 1", skipped)
+	}
+}
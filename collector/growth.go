@@ -0,0 +1,151 @@
+package collector
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// maxGrowthSamples bounds the number of samples kept per series, samples are
+// spread evenly over the growth window.
+const maxGrowthSamples = 60
+
+const secondsPerDay = 24 * 60 * 60
+
+// Kinds of series tracked in the storage history.
+const (
+	growthRepoUsed      = "repoUsed"
+	growthRepoItems     = "repoItems"
+	growthFilestoreUsed = "filestoreUsed"
+)
+
+type growthKey struct {
+	kind   string
+	labels [3]string
+}
+
+type growthSample struct {
+	at    time.Time
+	value float64
+}
+
+// storageHistory keeps a short in-memory history of storage values across
+// scrapes to derive linear growth rates from it.
+type storageHistory struct {
+	mutex        sync.Mutex
+	window       time.Duration
+	lastRecorded time.Time
+	series       map[growthKey][]growthSample
+}
+
+func newStorageHistory(window time.Duration) *storageHistory {
+	return &storageHistory{
+		window: window,
+		series: map[growthKey][]growthSample{},
+	}
+}
+
+// record adds values as one sample of each series. Samples closer than
+// window/maxGrowthSamples to the previous one are dropped, samples older than
+// the window are pruned and series missing from values (e.g. deleted
+// repositories) are forgotten.
+func (h *storageHistory) record(now time.Time, values map[growthKey]float64) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if !h.lastRecorded.IsZero() && now.Sub(h.lastRecorded) < h.window/maxGrowthSamples {
+		return
+	}
+	h.lastRecorded = now
+	for key := range h.series {
+		if _, ok := values[key]; !ok {
+			delete(h.series, key)
+		}
+	}
+	for key, value := range values {
+		samples := append(h.series[key], growthSample{at: now, value: value})
+		first := 0
+		for first < len(samples)-1 && now.Sub(samples[first].at) > h.window {
+			first++
+		}
+		h.series[key] = samples[first:]
+	}
+}
+
+// ratePerDay returns the change per day between the oldest and the newest
+// sample of a series. ok is false until the series has two samples.
+func (h *storageHistory) ratePerDay(key growthKey) (rate float64, ok bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	samples := h.series[key]
+	if len(samples) < 2 {
+		return 0, false
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	span := last.at.Sub(first.at).Seconds()
+	if span <= 0 {
+		return 0, false
+	}
+	return (last.value - first.value) / span * secondsPerDay, true
+}
+
+// exportStorageGrowth records the current storage summary in the storage
+// history and exports the growth rates of repositories and file stores, and
+// the number of days until each file store is full at its current growth rate.
+func (e *Exporter) exportStorageGrowth(storageInfo artifactory.StorageInfo, repoSummaries []repoSummary, ch chan<- prometheus.Metric) {
+	values := map[growthKey]float64{}
+	for _, rs := range repoSummaries {
+		labels := [3]string{rs.Name, rs.Type, rs.PackageType}
+		values[growthKey{growthRepoUsed, labels}] = rs.UsedSpace
+		values[growthKey{growthRepoItems, labels}] = rs.ItemsCount
+	}
+	// map[<file store labels>] <free bytes>
+	freeSpace := map[[3]string]float64{}
+	for _, store := range storageInfo.FileStoreSummaries {
+		labels := [3]string{strings.ToLower(store.StorageType), store.StorageDirectory}
+		used, _, err := e.convArtiToPromFileStoreData(store.UsedSpace)
+		if err != nil {
+			e.logger.Debug(
+				"Couldn't parse file store used space, skipping its growth",
+				"storage_type", labels[0],
+				"storage_dir", labels[1],
+				"err", err.Error(),
+			)
+			continue
+		}
+		values[growthKey{growthFilestoreUsed, labels}] = used
+		if free, _, err := e.convArtiToPromFileStoreData(store.FreeSpace); err == nil {
+			freeSpace[labels] = free
+		}
+	}
+	e.storageHistory.record(time.Now(), values)
+
+	for key := range values {
+		rate, ok := e.storageHistory.ratePerDay(key)
+		if !ok {
+			continue
+		}
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", key.kind+"Growth",
+			"labels", key.labels,
+			"value", rate,
+		)
+		switch key.kind {
+		case growthRepoUsed:
+			ch <- prometheus.MustNewConstMetric(storageGrowthMetrics["repoGrowth"], prometheus.GaugeValue, rate, key.labels[0], key.labels[1], key.labels[2], storageInfo.NodeId)
+		case growthRepoItems:
+			ch <- prometheus.MustNewConstMetric(storageGrowthMetrics["repoItemsGrowth"], prometheus.GaugeValue, rate, key.labels[0], key.labels[1], key.labels[2], storageInfo.NodeId)
+		case growthFilestoreUsed:
+			ch <- prometheus.MustNewConstMetric(storageGrowthMetrics["filestoreGrowth"], prometheus.GaugeValue, rate, key.labels[0], key.labels[1], storageInfo.NodeId)
+			free, ok := freeSpace[key.labels]
+			if !ok || rate <= 0 {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(storageGrowthMetrics["filestoreDaysUntilFull"], prometheus.GaugeValue, free/rate, key.labels[0], key.labels[1], storageInfo.NodeId)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package collector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStorageHistory(t *testing.T) {
+	history := newStorageHistory(time.Hour)
+	key := growthKey{growthRepoUsed, [3]string{"libs-release", "local", "maven"}}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	history.record(start, map[growthKey]float64{key: 1000})
+	if _, ok := history.ratePerDay(key); ok {
+		t.Error("Expected no rate with a single sample")
+	}
+
+	// Samples closer than window/maxGrowthSamples are dropped
+	history.record(start.Add(30*time.Second), map[growthKey]float64{key: 5000})
+	if _, ok := history.ratePerDay(key); ok {
+		t.Error("Expected sample within the minimum interval to be dropped")
+	}
+
+	history.record(start.Add(30*time.Minute), map[growthKey]float64{key: 2000})
+	rate, ok := history.ratePerDay(key)
+	if !ok || rate != 48000 {
+		t.Errorf("ratePerDay() = %v, %v, want 48000, true", rate, ok)
+	}
+
+	// Samples older than the window are pruned
+	history.record(start.Add(2*time.Hour), map[growthKey]float64{key: 2000})
+	history.record(start.Add(150*time.Minute), map[growthKey]float64{key: 2500})
+	rate, ok = history.ratePerDay(key)
+	if !ok || rate != 24000 {
+		t.Errorf("ratePerDay() after pruning = %v, %v, want 24000, true", rate, ok)
+	}
+
+	// Series missing from a sample are forgotten
+	history.record(start.Add(3*time.Hour), map[growthKey]float64{})
+	if _, ok := history.ratePerDay(key); ok {
+		t.Error("Expected removed series to be forgotten")
+	}
+}
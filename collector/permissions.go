@@ -0,0 +1,165 @@
+package collector
+
+import (
+	"context"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// permissionActions are the actions for which the principals holding them are
+// counted. Permission targets have no admin action: manage is the admin right
+// of a permission target, and Artifactory administrators are reported by the
+// user_details and group_details optional metrics.
+var permissionActions = []string{"delete", "manage"}
+
+// permissionSummary summarizes the permission targets for the access model metrics.
+type permissionSummary struct {
+	uncoveredRepos      float64
+	targetsMissingRepos float64
+	// map[<principal type>][<action>] <count>
+	principals map[string]map[string]float64
+}
+
+// summarizePermissionTargets checks the repositories of the permission targets
+// against the existing repositories, given by key with their lower case class,
+// and counts the distinct users and groups holding each of permissionActions.
+// A permission target covers repositories by key, ANY, or ANY <class> such as
+// ANY LOCAL. Virtual repositories are not covered by permission targets.
+func summarizePermissionTargets(targets []artifactory.PermissionTargetDetails, repos map[string]string) permissionSummary {
+	covered := map[string]bool{}
+	coveredClasses := map[string]bool{}
+	principals := map[string]map[string]map[string]bool{"user": {}, "group": {}}
+	for _, principalType := range []string{"user", "group"} {
+		for _, action := range permissionActions {
+			principals[principalType][action] = map[string]bool{}
+		}
+	}
+	summary := permissionSummary{}
+
+	for _, target := range targets {
+		if target.Repo != nil {
+			missing := false
+			for _, repo := range target.Repo.Repositories {
+				switch {
+				case repo == "ANY":
+					coveredClasses["*"] = true
+				case strings.HasPrefix(repo, "ANY "):
+					coveredClasses[strings.ToLower(strings.TrimPrefix(repo, "ANY "))] = true
+				default:
+					if _, ok := repos[repo]; ok {
+						covered[repo] = true
+					} else {
+						missing = true
+					}
+				}
+			}
+			if missing {
+				summary.targetsMissingRepos++
+			}
+		}
+		for _, section := range []*artifactory.PermissionTargetSection{target.Repo, target.Build, target.ReleaseBundle} {
+			if section == nil {
+				continue
+			}
+			for principalType, actions := range map[string]map[string][]string{"user": section.Actions.Users, "group": section.Actions.Groups} {
+				for name, granted := range actions {
+					for _, action := range granted {
+						if holders, ok := principals[principalType][action]; ok {
+							holders[name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for key, rclass := range repos {
+		if rclass == "virtual" || covered[key] || coveredClasses["*"] || coveredClasses[rclass] {
+			continue
+		}
+		summary.uncoveredRepos++
+	}
+	summary.principals = map[string]map[string]float64{}
+	for principalType, actions := range principals {
+		summary.principals[principalType] = map[string]float64{}
+		for action, holders := range actions {
+			summary.principals[principalType][action] = float64(len(holders))
+		}
+	}
+	return summary
+}
+
+// newPermissionTargetsCollector returns a scheduled collector fetching the
+// details of every permission target.
+func (e *Exporter) newPermissionTargetsCollector() *scheduledCollector {
+	return &scheduledCollector{
+		name:     collectorPermissions,
+		interval: e.exporterRuntimeConfig.PermissionTargetsInterval,
+		descs: []*prometheus.Desc{
+			permissionTargetMetrics["targets"],
+			permissionTargetMetrics["uncoveredRepos"],
+			permissionTargetMetrics["targetsMissingRepos"],
+			permissionTargetMetrics["principals"],
+		},
+		collect: e.exportPermissionTargets,
+	}
+}
+
+// exportPermissionTargets exports the permission target and access model
+// metrics. The details of each permission target take one API call.
+func (e *Exporter) exportPermissionTargets(ctx context.Context, ch chan<- prometheus.Metric) error {
+	targets, err := e.client.FetchPermissionTargets()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching security/permissions",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return err
+	}
+	repositories, err := e.client.FetchRepositories("")
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching repositories",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return err
+	}
+	repos := make(map[string]string, len(repositories.Repositories))
+	for _, repo := range repositories.Repositories {
+		repos[repo.Key] = strings.ToLower(repo.Type)
+	}
+
+	fetchedDetails := fetchEach(ctx, e, collectorPermissions, targets.PermissionTargets,
+		func(target artifactory.PermissionTarget) string { return target.Name },
+		func(target artifactory.PermissionTarget) (artifactory.PermissionTargetDetails, error) {
+			return e.client.FetchPermissionTarget(target.Name)
+		},
+	)
+	details := make([]artifactory.PermissionTargetDetails, 0, len(fetchedDetails))
+	for _, targetDetails := range fetchedDetails {
+		details = append(details, targetDetails.value)
+	}
+	summary := summarizePermissionTargets(details, repos)
+
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "permissionTargets",
+		"value", len(targets.PermissionTargets),
+		"uncoveredRepos", summary.uncoveredRepos,
+		"targetsMissingRepos", summary.targetsMissingRepos,
+	)
+	ch <- prometheus.MustNewConstMetric(permissionTargetMetrics["targets"], prometheus.GaugeValue, float64(len(targets.PermissionTargets)), targets.NodeId)
+	ch <- prometheus.MustNewConstMetric(permissionTargetMetrics["uncoveredRepos"], prometheus.GaugeValue, summary.uncoveredRepos, targets.NodeId)
+	ch <- prometheus.MustNewConstMetric(permissionTargetMetrics["targetsMissingRepos"], prometheus.GaugeValue, summary.targetsMissingRepos, targets.NodeId)
+	for principalType, actions := range summary.principals {
+		for action, count := range actions {
+			ch <- prometheus.MustNewConstMetric(permissionTargetMetrics["principals"], prometheus.GaugeValue, count, principalType, action, targets.NodeId)
+		}
+	}
+	return nil
+}
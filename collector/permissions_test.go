@@ -0,0 +1,100 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+func TestSummarizePermissionTargets(t *testing.T) {
+	var targets []artifactory.PermissionTargetDetails
+	if err := json.Unmarshal([]byte(`[
+		{"name":"remotes","repo":{"repositories":["ANY REMOTE"],"actions":{"groups":{"readers":["read"]}}}},
+		{"name":"libs","repo":{"repositories":["libs-release","removed-repo"],"actions":{
+			"users":{"alice":["read","write","delete"],"bob":["read","manage","delete"]},
+			"groups":{"admins":["manage"]}
+		}}},
+		{"name":"builds","build":{"repositories":["artifactory-build-info"],"actions":{"users":{"alice":["delete"]}}}}
+	]`), &targets); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	repos := map[string]string{
+		"libs-release":  "local",
+		"libs-snapshot": "local",
+		"maven-remote":  "remote",
+		"maven-virtual": "virtual",
+	}
+
+	summary := summarizePermissionTargets(targets, repos)
+	if summary.uncoveredRepos != 1 {
+		t.Errorf("uncovered repos = %v, want 1", summary.uncoveredRepos)
+	}
+	if summary.targetsMissingRepos != 1 {
+		t.Errorf("targets with missing repos = %v, want 1", summary.targetsMissingRepos)
+	}
+	want := map[string]map[string]float64{
+		"user":  {"delete": 2, "manage": 1},
+		"group": {"delete": 0, "manage": 1},
+	}
+	for principalType, actions := range want {
+		for action, count := range actions {
+			if got := summary.principals[principalType][action]; got != count {
+				t.Errorf("%s principals with %s = %v, want %v", principalType, action, got, count)
+			}
+		}
+	}
+}
+
+func TestExportPermissionTargetsSkipsFailedTargets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/security/permissions":
+			w.Write([]byte(`[
+				{"name":"libs","uri":"http://localhost/artifactory/api/v2/security/permissions/libs"},
+				{"name":"removed","uri":"http://localhost/artifactory/api/v2/security/permissions/removed"}
+			]`))
+		case "/api/v2/security/permissions/libs":
+			w.Write([]byte(`{"name":"libs","repo":{"repositories":["libs-release"],"actions":{"users":{"alice":["read","delete"]}}}}`))
+		case "/api/repositories":
+			w.Write([]byte(`[{"key":"libs-release","type":"LOCAL","packageType":"Maven"}]`))
+		default:
+			// removed was deleted since the permission targets were listed
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+
+	ch := make(chan prometheus.Metric, 10)
+	if err := e.track(collectorPermissions, func() error { return e.exportPermissionTargets(context.Background(), ch) }); err != nil {
+		t.Fatalf("exportPermissionTargets() error = %v", err)
+	}
+	close(ch)
+	var targets, uncovered float64
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		switch m.Desc() {
+		case permissionTargetMetrics["targets"]:
+			targets = metric.GetGauge().GetValue()
+		case permissionTargetMetrics["uncoveredRepos"]:
+			uncovered = metric.GetGauge().GetValue()
+		}
+	}
+	if targets != 2 || uncovered != 0 {
+		t.Errorf("targets = %v, uncovered repos = %v, want 2, 0", targets, uncovered)
+	}
+	if skipped := e.Status().Collectors[collectorPermissions].LastSkipped; skipped != 1 {
+		t.Errorf("LastSkipped = %d, want 1", skipped)
+	}
+}
@@ -0,0 +1,114 @@
+package collector
+
+import (
+	"context"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// Project member types, as used in the Access projects API paths.
+var projectMemberTypes = map[string]string{
+	"user":  "users",
+	"group": "groups",
+}
+
+// projectDetails holds the repositories and members of a project.
+type projectDetails struct {
+	repositories artifactory.Repositories
+	// map[<member type>] <members>
+	members map[string][]artifactory.ProjectMember
+}
+
+// fetchProjectDetails fetches the repositories and the members of a project.
+func (e *Exporter) fetchProjectDetails(project artifactory.Project) (projectDetails, error) {
+	details := projectDetails{members: make(map[string][]artifactory.ProjectMember, len(projectMemberTypes))}
+	var err error
+	details.repositories, err = e.client.FetchProjectRepositories(project.Key)
+	if err != nil {
+		return details, err
+	}
+	for memberType, endpoint := range projectMemberTypes {
+		details.members[memberType], err = e.client.FetchProjectMembers(project.Key, endpoint)
+		if err != nil {
+			return details, err
+		}
+	}
+	return details, nil
+}
+
+// exportProjects exports JFrog projects with their repositories, storage and members.
+// The used space of a project is the sum of the used space of its repositories,
+// as reported in the storage summary, where the cache of a remote repository
+// is listed as <key>-cache.
+func (e *Exporter) exportProjects(repoSummaries []repoSummary, ch chan<- prometheus.Metric) error {
+	projects, err := e.client.FetchProjects()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching projects",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return err
+	}
+
+	// map[<repo key>] <used bytes>
+	usedSpace := make(map[string]float64, len(repoSummaries))
+	for _, rs := range repoSummaries {
+		usedSpace[rs.Name] = rs.UsedSpace
+	}
+
+	details := fetchEach(context.Background(), e, collectorProjects, projects.Projects,
+		func(project artifactory.Project) string { return project.Key },
+		e.fetchProjectDetails,
+	)
+	for _, projectDetails := range details {
+		project, repositories := projectDetails.item, projectDetails.value.repositories
+		var used float64
+		for _, repo := range repositories.Repositories {
+			used += usedSpace[repo.Key]
+			if strings.EqualFold(repo.Type, "remote") {
+				used += usedSpace[repo.Key+"-cache"]
+			}
+		}
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "projectUsedBytes",
+			"project", project.Key,
+			"repositories", len(repositories.Repositories),
+			"value", used,
+		)
+		ch <- prometheus.MustNewConstMetric(projectMetrics["info"], prometheus.GaugeValue, 1, project.Key, project.DisplayName, projects.NodeId)
+		ch <- prometheus.MustNewConstMetric(projectMetrics["repositories"], prometheus.GaugeValue, float64(len(repositories.Repositories)), project.Key, projects.NodeId)
+		ch <- prometheus.MustNewConstMetric(projectMetrics["usedBytes"], prometheus.GaugeValue, used, project.Key, projects.NodeId)
+		// Access reports -1 for projects without a storage quota
+		if project.StorageQuotaBytes > 0 {
+			quota := float64(project.StorageQuotaBytes)
+			ch <- prometheus.MustNewConstMetric(projectMetrics["quotaBytes"], prometheus.GaugeValue, quota, project.Key, projects.NodeId)
+			ch <- prometheus.MustNewConstMetric(projectMetrics["quotaUtilization"], prometheus.GaugeValue, used/quota, project.Key, projects.NodeId)
+		}
+
+		for memberType, members := range projectDetails.value.members {
+			roles := map[string]float64{}
+			for _, member := range members {
+				for _, role := range member.Roles {
+					roles[role]++
+				}
+			}
+			e.logger.Debug(
+				logDbgMsgRegMetric,
+				"metric", "projectMembers",
+				"project", project.Key,
+				"type", memberType,
+				"value", len(members),
+			)
+			ch <- prometheus.MustNewConstMetric(projectMetrics["members"], prometheus.GaugeValue, float64(len(members)), project.Key, memberType, projects.NodeId)
+			for role, count := range roles {
+				ch <- prometheus.MustNewConstMetric(projectMetrics["roleMembers"], prometheus.GaugeValue, count, project.Key, role, memberType, projects.NodeId)
+			}
+		}
+	}
+	return nil
+}
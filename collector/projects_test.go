@@ -0,0 +1,87 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestExportProjectsSkipsFailedProjects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/system/ping":
+			w.Write([]byte("OK"))
+		case "/access/api/v1/projects":
+			w.Write([]byte(`[
+				{"project_key":"alpha","display_name":"Alpha","storage_quota_bytes":1000},
+				{"project_key":"beta","display_name":"Beta","storage_quota_bytes":-1}
+			]`))
+		case "/api/repositories":
+			if r.URL.Query().Get("project") == "alpha" {
+				w.Write([]byte(`[
+					{"key":"alpha-libs","type":"LOCAL","packageType":"Maven"},
+					{"key":"alpha-central","type":"REMOTE","packageType":"Maven"}
+				]`))
+				return
+			}
+			w.Write([]byte(`[]`))
+		case "/access/api/v1/projects/alpha/users":
+			w.Write([]byte(`{"members":[{"name":"alice","roles":["Developer","Viewer"]},{"name":"bob","roles":["Viewer"]}]}`))
+		case "/access/api/v1/projects/alpha/groups":
+			w.Write([]byte(`{"members":[]}`))
+		default:
+			// beta was deleted since it was listed
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+
+	ch := make(chan prometheus.Metric, 20)
+	summaries := []repoSummary{
+		{Name: "alpha-libs", UsedSpace: 250},
+		{Name: "alpha-central", UsedSpace: 0},
+		// The storage summary lists the cache of a remote repository on its own
+		{Name: "alpha-central-cache", UsedSpace: 150},
+	}
+	err := e.track(collectorProjects, func() error { return e.exportProjects(summaries, ch) })
+	if err != nil {
+		t.Fatalf("exportProjects() error = %v", err)
+	}
+	close(ch)
+	var projects []string
+	var utilization float64
+	userRoles := map[string]float64{}
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		switch m.Desc() {
+		case projectMetrics["info"]:
+			projects = append(projects, labelValue(&metric, "project"))
+		case projectMetrics["quotaUtilization"]:
+			utilization = metric.GetGauge().GetValue()
+		case projectMetrics["roleMembers"]:
+			if labelValue(&metric, "type") == "user" {
+				userRoles[labelValue(&metric, "role")] = metric.GetGauge().GetValue()
+			}
+		}
+	}
+	if len(projects) != 1 || projects[0] != "alpha" {
+		t.Errorf("Expected only project alpha, got %v", projects)
+	}
+	if utilization != 0.4 {
+		t.Errorf("Quota utilization = %v, want 0.4", utilization)
+	}
+	if userRoles["Viewer"] != 2 || userRoles["Developer"] != 1 {
+		t.Errorf("Unexpected user role members %v", userRoles)
+	}
+	if skipped := e.Status().Collectors[collectorProjects].LastSkipped; skipped != 1 {
+		t.Errorf("LastSkipped = %d, want 1", skipped)
+	}
+}
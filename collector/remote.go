@@ -0,0 +1,170 @@
+package collector
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// maxConcurrentUpstreamProbes bounds the number of remote repository URLs probed at once.
+const maxConcurrentUpstreamProbes = 8
+
+const (
+	remoteStatusOnline     = "online"
+	remoteStatusOffline    = "offline"
+	remoteStatusBlackedOut = "blacked_out"
+)
+
+type remoteRepo struct {
+	Name        string
+	PackageType string
+	URL         string
+	Status      string
+	NodeId      string
+}
+
+// remoteRepoStatus returns the status of a repository from the offline and
+// blackedOut flags of its configuration. Local and federated repositories can
+// be blacked out too.
+func remoteRepoStatus(repoConfig artifactory.RepositoryConfig) string {
+	switch {
+	case repoConfig.BlackedOut:
+		return remoteStatusBlackedOut
+	case repoConfig.Offline:
+		return remoteStatusOffline
+	default:
+		return remoteStatusOnline
+	}
+}
+
+// newRemoteRepo returns a remote repository from its configuration.
+func newRemoteRepo(repoConfig artifactory.RepositoryConfig) remoteRepo {
+	return remoteRepo{
+		Name:        repoConfig.Key,
+		PackageType: strings.ToLower(repoConfig.PackageType),
+		URL:         repoConfig.URL,
+		Status:      remoteRepoStatus(repoConfig),
+		NodeId:      repoConfig.NodeId,
+	}
+}
+
+// fetchRemoteRepos lists the remote repositories with their configuration.
+func (e *Exporter) fetchRemoteRepos(ctx context.Context, collector string) ([]remoteRepo, error) {
+	repositories, err := e.client.FetchRepositories("remote")
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching remote repositories",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return nil, err
+	}
+	if len(repositories.Repositories) == 0 {
+		e.logger.Debug("No remote repositories found")
+		return nil, nil
+	}
+
+	configs := fetchEach(ctx, e, collector, repositories.Repositories,
+		func(repo artifactory.Repository) string { return repo.Key },
+		func(repo artifactory.Repository) (artifactory.RepositoryConfig, error) {
+			return e.client.FetchRepositoryConfig(repo.Key)
+		},
+	)
+	remotes := make([]remoteRepo, 0, len(configs))
+	for _, fetchedConfig := range configs {
+		remotes = append(remotes, newRemoteRepo(fetchedConfig.value))
+	}
+	return remotes, nil
+}
+
+func (e *Exporter) exportRemoteRepositories(ch chan<- prometheus.Metric) error {
+	snapshot, err := e.lastRepoConfigs()
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range snapshot.repositories {
+		repoConfig, exists := snapshot.configs[repo.Key]
+		if !exists || !strings.EqualFold(repo.Type, "remote") {
+			continue
+		}
+		remote := newRemoteRepo(repoConfig)
+		online := convArtiToPromBool(remote.Status == remoteStatusOnline)
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "remoteRepoOnline",
+			"repo", remote.Name,
+			"package_type", remote.PackageType,
+			"url", remote.URL,
+			"status", remote.Status,
+			"value", online,
+		)
+		ch <- prometheus.MustNewConstMetric(remoteRepoMetrics["online"], prometheus.GaugeValue, online, remote.Name, remote.PackageType, remote.URL, remote.NodeId)
+		ch <- prometheus.MustNewConstMetric(remoteRepoMetrics["status"], prometheus.GaugeValue, 1, remote.Name, remote.PackageType, remote.URL, remote.Status, remote.NodeId)
+	}
+	return nil
+}
+
+// newUpstreamProbesCollector returns a scheduled collector probing the
+// upstream URL of every remote repository, so slow upstreams never delay
+// scrapes.
+func (e *Exporter) newUpstreamProbesCollector() *scheduledCollector {
+	return &scheduledCollector{
+		name:     collectorUpstreamProbes,
+		interval: e.exporterRuntimeConfig.RemoteRepoURLCheckInterval,
+		descs: []*prometheus.Desc{
+			upstreamProbeMetrics["urlReachable"],
+			upstreamProbeMetrics["urlProbeDuration"],
+		},
+		collect: e.exportUpstreamProbes,
+	}
+}
+
+// exportUpstreamProbes checks the reachability of the upstream URL of each
+// remote repository with bounded concurrency.
+func (e *Exporter) exportUpstreamProbes(ctx context.Context, ch chan<- prometheus.Metric) error {
+	remotes, err := e.fetchRemoteRepos(ctx, collectorUpstreamProbes)
+	if err != nil {
+		return err
+	}
+
+	probes := make([]artifactory.UpstreamProbe, len(remotes))
+	sem := make(chan struct{}, maxConcurrentUpstreamProbes)
+	var wg sync.WaitGroup
+	for i, remote := range remotes {
+		if remote.URL == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			probes[i] = e.client.ProbeUpstream(url)
+		}(i, remote.URL)
+	}
+	wg.Wait()
+
+	for i, remote := range remotes {
+		if remote.URL == "" {
+			continue
+		}
+		probe := probes[i]
+		reachable := convArtiToPromBool(probe.Reachable)
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "remoteRepoURLReachable",
+			"repo", remote.Name,
+			"url", remote.URL,
+			"status_code", probe.StatusCode,
+			"value", reachable,
+		)
+		ch <- prometheus.MustNewConstMetric(upstreamProbeMetrics["urlReachable"], prometheus.GaugeValue, reachable, remote.Name, remote.PackageType, remote.URL, remote.NodeId)
+		ch <- prometheus.MustNewConstMetric(upstreamProbeMetrics["urlProbeDuration"], prometheus.GaugeValue, probe.Duration.Seconds(), remote.Name, remote.PackageType, remote.URL, remote.NodeId)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// errRepoConfigsPending is returned by the repository collectors until the
+// repository configurations were fetched once.
+var errRepoConfigsPending = errors.New("repository configurations not fetched yet")
+
+// repoConfigSnapshot holds the repositories and their configurations, shared
+// by the repositories, remote_repositories and virtual_repositories collectors.
+type repoConfigSnapshot struct {
+	repositories []artifactory.Repository
+	// map[<repo key>] <configuration>, without the repositories whose
+	// configuration could not be fetched
+	configs map[string]artifactory.RepositoryConfig
+	// map[<repo key>] <deleted since the repositories were listed>
+	missing map[string]bool
+	nodeId  string
+}
+
+// repoConfigStore keeps the last repository configurations.
+type repoConfigStore struct {
+	mutex    sync.RWMutex
+	snapshot *repoConfigSnapshot
+}
+
+// repoConfig is the configuration of a repository, or whether it was
+// deleted since the repositories were listed.
+type repoConfig struct {
+	config  artifactory.RepositoryConfig
+	missing bool
+}
+
+// newRepoConfigsCollector returns a scheduled collector fetching the
+// configuration of every repository. It exports no metrics itself.
+func (e *Exporter) newRepoConfigsCollector() *scheduledCollector {
+	return &scheduledCollector{
+		name:     collectorRepoConfigs,
+		interval: e.exporterRuntimeConfig.RepoConfigInterval,
+		collect:  e.fetchRepoConfigs,
+	}
+}
+
+// fetchRepoConfigs fetches the configuration of the repositories used by the
+// enabled collectors, all of them unless only remote_repositories is enabled.
+func (e *Exporter) fetchRepoConfigs(ctx context.Context, _ chan<- prometheus.Metric) error {
+	repositories, err := e.client.FetchRepositories("")
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching repositories",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return err
+	}
+	optional := e.exporterRuntimeConfig.OptionalMetrics
+	var fetch []artifactory.Repository
+	for _, repo := range repositories.Repositories {
+		if optional.Repositories || optional.VirtualRepositories || strings.EqualFold(repo.Type, "remote") {
+			fetch = append(fetch, repo)
+		}
+	}
+
+	snapshot := &repoConfigSnapshot{
+		repositories: repositories.Repositories,
+		configs:      make(map[string]artifactory.RepositoryConfig, len(fetch)),
+		missing:      map[string]bool{},
+		nodeId:       repositories.NodeId,
+	}
+	for _, fetchedConfig := range fetchEach(ctx, e, collectorRepoConfigs, fetch,
+		func(repo artifactory.Repository) string { return repo.Key },
+		func(repo artifactory.Repository) (repoConfig, error) {
+			conf, err := e.client.FetchRepositoryConfig(repo.Key)
+			if artifactory.IsNotFound(err) {
+				return repoConfig{missing: true}, nil
+			}
+			return repoConfig{config: conf}, err
+		},
+	) {
+		if fetchedConfig.value.missing {
+			snapshot.missing[fetchedConfig.item.Key] = true
+			continue
+		}
+		snapshot.configs[fetchedConfig.item.Key] = fetchedConfig.value.config
+	}
+
+	e.repoConfigs.mutex.Lock()
+	e.repoConfigs.snapshot = snapshot
+	e.repoConfigs.mutex.Unlock()
+	return nil
+}
+
+// lastRepoConfigs returns the last repository configurations.
+func (e *Exporter) lastRepoConfigs() (*repoConfigSnapshot, error) {
+	e.repoConfigs.mutex.RLock()
+	defer e.repoConfigs.mutex.RUnlock()
+	if e.repoConfigs.snapshot == nil {
+		return nil, errRepoConfigsPending
+	}
+	return e.repoConfigs.snapshot, nil
+}
+
+// map[<rclass>/<package type>] <count>
+type repoClassCounts map[[2]string]float64
+
+func (e *Exporter) exportRepositories(ch chan<- prometheus.Metric) error {
+	snapshot, err := e.lastRepoConfigs()
+	if err != nil {
+		return err
+	}
+
+	counts := repoClassCounts{}
+	for _, repo := range snapshot.repositories {
+		counts[[2]string{strings.ToLower(repo.Type), strings.ToLower(repo.PackageType)}]++
+	}
+	for key, count := range counts {
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "repositoriesCount",
+			"rclass", key[0],
+			"package_type", key[1],
+			"value", count,
+		)
+		ch <- prometheus.MustNewConstMetric(repositoriesMetrics["count"], prometheus.GaugeValue, count, key[0], key[1], snapshot.nodeId)
+	}
+
+	for _, repo := range snapshot.repositories {
+		repoConfig, exists := snapshot.configs[repo.Key]
+		if !exists {
+			continue
+		}
+		rclass := strings.ToLower(repoConfig.Rclass)
+		packageType := strings.ToLower(repoConfig.PackageType)
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "repositoriesInfo",
+			"repo", repoConfig.Key,
+			"rclass", rclass,
+			"package_type", packageType,
+			"blacked_out", repoConfig.BlackedOut,
+			"offline", repoConfig.Offline,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			repositoriesMetrics["info"],
+			prometheus.GaugeValue,
+			1,
+			repoConfig.Key,
+			rclass,
+			packageType,
+			strconv.FormatBool(repoConfig.XrayIndex),
+			strconv.FormatBool(repoConfig.HandleReleases),
+			strconv.FormatBool(repoConfig.HandleSnapshots),
+			strconv.FormatBool(repoConfig.BlackedOut),
+			strconv.FormatBool(repoConfig.Offline),
+			repoConfig.NodeId,
+		)
+	}
+	return nil
+}
+
+// itemRepository is a repository holding items, as named in AQL queries.
+type itemRepository struct {
+	key         string
+	rclass      string
+	packageType string
+}
+
+// fetchItemRepositories returns the local and federated repositories and the
+// caches of the remote repositories, which hold the items found by AQL.
+// Remote repository caches are named <key>-cache with rclass cache, as in the
+// storage summary.
+func (e *Exporter) fetchItemRepositories() ([]itemRepository, string, error) {
+	repositories, err := e.client.FetchRepositories("")
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching repositories",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return nil, repositories.NodeId, err
+	}
+	var itemRepositories []itemRepository
+	for _, repo := range repositories.Repositories {
+		packageType := strings.ToLower(repo.PackageType)
+		switch rclass := strings.ToLower(repo.Type); rclass {
+		case "local", "federated":
+			itemRepositories = append(itemRepositories, itemRepository{key: repo.Key, rclass: rclass, packageType: packageType})
+		case "remote":
+			itemRepositories = append(itemRepositories, itemRepository{key: repo.Key + "-cache", rclass: "cache", packageType: packageType})
+		}
+	}
+	return itemRepositories, repositories.NodeId, nil
+}
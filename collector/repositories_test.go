@@ -0,0 +1,90 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// labelValue returns the value of the label of a metric, empty if it has no such label.
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
+func TestExportRepositoriesSkipsMissingConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/repositories":
+			w.Write([]byte(`[
+				{"key":"libs-release","type":"LOCAL","packageType":"Maven"},
+				{"key":"libs-deleted","type":"LOCAL","packageType":"Maven"},
+				{"key":"maven-central","type":"REMOTE","packageType":"Maven"}
+			]`))
+		case "/api/repositories/maven-central":
+			w.Write([]byte(`{"key":"maven-central","rclass":"remote","packageType":"maven","blackedOut":true}`))
+		case "/api/repositories/libs-release":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"errors":[{"status":500,"message":"Internal Server Error"}]}`))
+		default:
+			// libs-deleted was deleted since it was listed
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+	e.exporterRuntimeConfig.OptionalMetrics.Repositories = true
+
+	ch := make(chan prometheus.Metric, 10)
+	if err := e.exportRepositories(ch); err != errRepoConfigsPending {
+		t.Errorf("exportRepositories() error = %v, want %v before the configurations are fetched", err, errRepoConfigsPending)
+	}
+	if err := e.track(collectorRepoConfigs, func() error { return e.fetchRepoConfigs(context.Background(), nil) }); err != nil {
+		t.Fatalf("fetchRepoConfigs() error = %v", err)
+	}
+	if skipped := e.Status().Collectors[collectorRepoConfigs].LastSkipped; skipped != 1 {
+		t.Errorf("LastSkipped = %d, want 1", skipped)
+	}
+	if err := e.exportRemoteRepositories(ch); err != nil {
+		t.Fatalf("exportRemoteRepositories() error = %v", err)
+	}
+	err := e.exportRepositories(ch)
+	if err != nil {
+		t.Fatalf("exportRepositories() error = %v", err)
+	}
+	close(ch)
+	var counts, infos []string
+	remoteStatus := ""
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		switch m.Desc() {
+		case remoteRepoMetrics["status"]:
+			remoteStatus = labelValue(&metric, "status")
+		case repositoriesMetrics["count"]:
+			counts = append(counts, labelValue(&metric, "rclass"))
+		case repositoriesMetrics["info"]:
+			infos = append(infos, labelValue(&metric, "name"))
+		}
+	}
+	if len(counts) != 2 {
+		t.Errorf("Expected a count per rclass, got %v", counts)
+	}
+	if len(infos) != 1 || infos[0] != "maven-central" {
+		t.Errorf("Expected only maven-central info, got %v", infos)
+	}
+	if remoteStatus != remoteStatusBlackedOut {
+		t.Errorf("maven-central status = %q, want %q", remoteStatus, remoteStatusBlackedOut)
+	}
+}
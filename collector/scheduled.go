@@ -0,0 +1,97 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// scheduledCollector runs an expensive collection on its own interval,
+// independently of scrapes. Scrapes serve the metrics of its last
+// successful run.
+type scheduledCollector struct {
+	name     string
+	interval time.Duration
+	descs    []*prometheus.Desc
+	collect  func(ctx context.Context, ch chan<- prometheus.Metric) error
+
+	mutex   sync.RWMutex
+	metrics []prometheus.Metric
+}
+
+// run executes the collection once and keeps its metrics if it succeeded.
+// A collection aborted because ctx is done is dropped.
+func (s *scheduledCollector) run(ctx context.Context, e *Exporter) {
+	ch := make(chan prometheus.Metric)
+	done := make(chan []prometheus.Metric)
+	go func() {
+		var metrics []prometheus.Metric
+		for m := range ch {
+			metrics = append(metrics, m)
+		}
+		done <- metrics
+	}()
+	err := e.track(s.name, func() error { return s.collect(ctx, ch) })
+	close(ch)
+	metrics := <-done
+	if ctx.Err() != nil {
+		e.logger.Debug("Scheduled collection aborted", "collector", s.name)
+		return
+	}
+	if err != nil {
+		e.logger.Warn(
+			"Scheduled collection failed, keeping previous metrics",
+			"collector", s.name,
+			"err", err.Error(),
+		)
+		return
+	}
+	s.mutex.Lock()
+	s.metrics = metrics
+	s.mutex.Unlock()
+}
+
+// loop runs the collection on startup and then on every interval until ctx is cancelled.
+func (s *scheduledCollector) loop(ctx context.Context, e *Exporter, wg *sync.WaitGroup) {
+	defer wg.Done()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		s.run(ctx, e)
+		select {
+		case <-ctx.Done():
+			e.logger.Debug("Stopping scheduled collector", "collector", s.name)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *scheduledCollector) describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range s.descs {
+		ch <- desc
+	}
+}
+
+func (s *scheduledCollector) export(ch chan<- prometheus.Metric) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for _, m := range s.metrics {
+		ch <- m
+	}
+}
+
+// startScheduled starts the scheduled collectors, they are stopped by Close.
+func (e *Exporter) startScheduled() {
+	if len(e.scheduled) == 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	e.stopScheduled = cancel
+	for _, s := range e.scheduled {
+		e.scheduledRuns.Add(1)
+		go s.loop(ctx, e, &e.scheduledRuns)
+	}
+}
@@ -0,0 +1,54 @@
+package collector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCloseAbortsScheduledCollection(t *testing.T) {
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/search/aql" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// The closed connection is only noticed once the body is read
+		io.Copy(io.Discard, r.Body)
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+
+	s := &scheduledCollector{
+		name:     "test",
+		interval: time.Hour,
+		collect: func(ctx context.Context, _ chan<- prometheus.Metric) error {
+			_, _, err := e.streamAQL(ctx, `items.find()`, skipAQLResult)
+			return err
+		},
+	}
+	e.scheduled = append(e.scheduled, s)
+	e.startScheduled()
+	<-started
+
+	closed := make(chan struct{})
+	go func() {
+		e.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close() did not abort the scheduled collection")
+	}
+	if s.metrics != nil {
+		t.Errorf("Expected the aborted collection to be dropped, got %d metrics", len(s.metrics))
+	}
+}
@@ -88,8 +88,8 @@ func (e *Exporter) exportUsersCount(metricName string, metric *prometheus.Desc,
 }
 
 type group struct {
-	Name  string `json:"name"`
-	Realm string `json:"uri"`
+	Name string `json:"name"`
+	URI  string `json:"uri"`
 }
 
 func (e *Exporter) exportGroups(metricName string, metric *prometheus.Desc, ch chan<- prometheus.Metric) error {
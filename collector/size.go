@@ -0,0 +1,72 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// newArtifactSizeCollector returns a scheduled collector building the
+// artifact size histogram of every repository.
+func (e *Exporter) newArtifactSizeCollector() *scheduledCollector {
+	return &scheduledCollector{
+		name:     collectorArtifactSize,
+		interval: e.exporterRuntimeConfig.ArtifactSizeInterval,
+		descs:    []*prometheus.Desc{artifactSizeMetrics["size"]},
+		collect:  e.exportArtifactSizes,
+	}
+}
+
+// exportArtifactSizes exports a histogram of the size of the files of every
+// local repository and remote repository cache. Only the sizes are queried
+// and they are added to the buckets as they are decoded.
+func (e *Exporter) exportArtifactSizes(ctx context.Context, ch chan<- prometheus.Metric) error {
+	repositories, nodeId, err := e.fetchItemRepositories()
+	if err != nil {
+		return err
+	}
+	bounds := e.exporterRuntimeConfig.ArtifactSizeBuckets
+
+	for _, repo := range repositories {
+		e.logger.Debug(
+			"Building artifact size histogram",
+			"repo", repo.key,
+		)
+		counts := make([]uint64, len(bounds))
+		var sum float64
+		criteria := fmt.Sprintf("{\"repo\" : %q, \"type\" : \"file\"}", repo.key)
+		total, err := e.queryItemsPaged(ctx, criteria, `"size"`, func(dec *json.Decoder) error {
+			var item artifactResult
+			if err := dec.Decode(&item); err != nil {
+				return err
+			}
+			sum += item.Size
+			if i := sort.SearchFloat64s(bounds, item.Size); i < len(bounds) {
+				counts[i]++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		// Prometheus buckets are cumulative
+		buckets := make(map[float64]uint64, len(bounds))
+		var cumulative uint64
+		for i, bound := range bounds {
+			cumulative += counts[i]
+			buckets[bound] = cumulative
+		}
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "artifactSize",
+			"repo", repo.key,
+			"count", total,
+			"sum", sum,
+		)
+		ch <- prometheus.MustNewConstHistogram(artifactSizeMetrics["size"], uint64(total), sum, buckets, repo.key, repo.rclass, nodeId)
+	}
+	return nil
+}
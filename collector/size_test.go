@@ -0,0 +1,45 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestExportArtifactSizes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/repositories":
+			w.Write([]byte(`[{"key":"libs-release","type":"LOCAL","packageType":"Maven"}]`))
+		case "/api/search/aql":
+			w.Write([]byte(`{"results":[{"size":10},{"size":100},{"size":1000},{"size":5000}]}`))
+		}
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+	e.exporterRuntimeConfig.ArtifactsAQLPageSize = 100
+	e.exporterRuntimeConfig.ArtifactSizeBuckets = []float64{100, 1000}
+
+	ch := make(chan prometheus.Metric, 1)
+	if err := e.exportArtifactSizes(context.Background(), ch); err != nil {
+		t.Fatalf("exportArtifactSizes() error = %v", err)
+	}
+	var metric dto.Metric
+	if err := (<-ch).Write(&metric); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	histogram := metric.GetHistogram()
+	if histogram.GetSampleCount() != 4 || histogram.GetSampleSum() != 6110 {
+		t.Errorf("count = %d, sum = %v, want 4, 6110", histogram.GetSampleCount(), histogram.GetSampleSum())
+	}
+	want := map[float64]uint64{100: 2, 1000: 3}
+	for _, b := range histogram.GetBucket() {
+		if b.GetCumulativeCount() != want[b.GetUpperBound()] {
+			t.Errorf("bucket %v = %d, want %d", b.GetUpperBound(), b.GetCumulativeCount(), want[b.GetUpperBound()])
+		}
+	}
+}
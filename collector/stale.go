@@ -0,0 +1,94 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// staleArtifactsTotals holds the count and size of stale artifacts.
+type staleArtifactsTotals struct {
+	count float64
+	bytes float64
+}
+
+// newStaleArtifactsCollector returns a scheduled collector searching the
+// artifacts not downloaded within each configured number of days.
+func (e *Exporter) newStaleArtifactsCollector() *scheduledCollector {
+	return &scheduledCollector{
+		name:     collectorStaleArtifacts,
+		interval: e.exporterRuntimeConfig.StaleArtifactsInterval,
+		descs:    []*prometheus.Desc{staleArtifactsMetrics["count"], staleArtifactsMetrics["bytes"]},
+		collect:  e.exportStaleArtifacts,
+	}
+}
+
+// exportStaleArtifacts exports, for every local repository and remote
+// repository cache, the number and size of the artifacts created before and
+// not downloaded within the last N days, split by whether they were ever downloaded.
+func (e *Exporter) exportStaleArtifacts(ctx context.Context, ch chan<- prometheus.Metric) error {
+	repositories, nodeId, err := e.fetchItemRepositories()
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range repositories {
+		for _, days := range e.exporterRuntimeConfig.StaleArtifactsDays {
+			totals, err := e.findStaleArtifacts(ctx, repo.key, days)
+			if err != nil {
+				return err
+			}
+			for never, t := range totals {
+				labels := []string{repo.key, repo.rclass, strconv.Itoa(days), strconv.FormatBool(never), nodeId}
+				e.logger.Debug(
+					logDbgMsgRegMetric,
+					"metric", "staleArtifacts",
+					"repo", repo.key,
+					"days", days,
+					"never_downloaded", never,
+					"value", t.count,
+				)
+				ch <- prometheus.MustNewConstMetric(staleArtifactsMetrics["count"], prometheus.GaugeValue, t.count, labels...)
+				ch <- prometheus.MustNewConstMetric(staleArtifactsMetrics["bytes"], prometheus.GaugeValue, t.bytes, labels...)
+			}
+		}
+	}
+	return nil
+}
+
+// findStaleArtifacts returns the totals of the stale artifacts of a
+// repository, by whether they were ever downloaded. The never downloaded and
+// the downloaded ones are searched by separate queries, so that only item
+// fields are included and the queries can be paged.
+func (e *Exporter) findStaleArtifacts(ctx context.Context, repo string, days int) (map[bool]staleArtifactsTotals, error) {
+	e.logger.Debug(
+		"Finding stale artifacts",
+		"repo", repo,
+		"days", days,
+	)
+	totals := map[bool]staleArtifactsTotals{}
+	for never, downloadCriteria := range map[bool]string{
+		true:  "\"stat.downloads\" : {\"$eq\" : null}",
+		false: fmt.Sprintf("\"stat.downloaded\" : {\"$before\" : \"%dd\"}", days),
+	} {
+		var t staleArtifactsTotals
+		criteria := fmt.Sprintf("{\"repo\" : %q, \"type\" : \"file\", \"created\" : {\"$before\" : \"%dd\"}, %s}", repo, days, downloadCriteria)
+		count, err := e.queryItemsPaged(ctx, criteria, `"size"`, func(dec *json.Decoder) error {
+			var item artifactResult
+			if err := dec.Decode(&item); err != nil {
+				return err
+			}
+			t.bytes += item.Size
+			return nil
+		})
+		if err != nil {
+			return totals, err
+		}
+		t.count = count
+		totals[never] = t
+	}
+	return totals, nil
+}
@@ -0,0 +1,81 @@
+package collector
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestExportStaleArtifacts(t *testing.T) {
+	var queried []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/repositories":
+			w.Write([]byte(`[
+				{"key":"libs-release","type":"LOCAL","packageType":"Maven"},
+				{"key":"maven-remote","type":"REMOTE","packageType":"Maven"},
+				{"key":"maven","type":"VIRTUAL","packageType":"Maven"}
+			]`))
+		case "/api/search/aql":
+			body, _ := io.ReadAll(r.Body)
+			queried = append(queried, string(body))
+			if !strings.Contains(string(body), `"$before" : "30d"`) {
+				t.Errorf("Expected query for 30 days, got %s", body)
+			}
+			if !strings.Contains(string(body), `.include("repo", "path", "name", "size").sort(`) {
+				t.Errorf("Expected a paged query including item fields only, got %s", body)
+			}
+			if strings.Contains(string(body), `"stat.downloads" : {"$eq" : null}`) {
+				w.Write([]byte(`{"results":[{"size":50},{"size":25}]}`))
+				return
+			}
+			w.Write([]byte(`{"results":[{"size":100}]}`))
+		}
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+	e.exporterRuntimeConfig.ArtifactsAQLPageSize = 100
+	e.exporterRuntimeConfig.StaleArtifactsDays = []int{30}
+
+	ch := make(chan prometheus.Metric, 20)
+	if err := e.exportStaleArtifacts(context.Background(), ch); err != nil {
+		t.Fatalf("exportStaleArtifacts() error = %v", err)
+	}
+	close(ch)
+
+	if len(queried) != 4 || !strings.Contains(queried[0], `"libs-release"`) || !strings.Contains(queried[3], `"maven-remote-cache"`) {
+		t.Fatalf("Unexpected queries %v", queried)
+	}
+	want := map[string]float64{
+		"stale_artifacts libs-release local false":             1,
+		"stale_artifacts_bytes libs-release local false":       100,
+		"stale_artifacts libs-release local true":              2,
+		"stale_artifacts_bytes libs-release local true":        75,
+		"stale_artifacts maven-remote-cache cache true":        2,
+		"stale_artifacts_bytes maven-remote-cache cache false": 100,
+	}
+	got := map[string]float64{}
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		labels := map[string]string{}
+		for _, l := range metric.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		name := strings.TrimPrefix(strings.Split(m.Desc().String(), `"`)[1], "artifactory_")
+		got[strings.Join([]string{name, labels["name"], labels["type"], labels["never_downloaded"]}, " ")] = metric.GetGauge().GetValue()
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %v", key, got[key], value)
+		}
+	}
+}
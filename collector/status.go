@@ -0,0 +1,321 @@
+package collector
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+var errNotHealthy = errors.New("artifactory system/ping did not return OK")
+
+// readyCheckWait bounds the time a readiness probe waits for a check of
+// Artifactory, the last result is served if the check takes longer.
+const readyCheckWait = time.Second
+
+// Collector names used to report the state of each collection step in Status.
+const (
+	collectorOpenMetrics      = "open_metrics"
+	collectorSystem           = "system"
+	collectorLicenses         = "licenses"
+	collectorStorage          = "storage"
+	collectorArtifacts        = "artifacts"
+	collectorFederation       = "federation"
+	collectorAccessFederation = "access_federation"
+	collectorBackgroundTasks  = "background_tasks"
+	collectorRepositories     = "repositories"
+	collectorRepoConfigs      = "repository_configs"
+	collectorRemoteRepos      = "remote_repositories"
+	collectorUpstreamProbes   = "remote_repo_url_check"
+	collectorVirtualRepos     = "virtual_repositories"
+	collectorProjects         = "projects"
+	collectorStaleArtifacts   = "stale_artifacts"
+	collectorTopArtifacts     = "top_artifacts"
+	collectorArtifactSize     = "artifact_size"
+	collectorDocker           = "docker"
+	collectorBuilds           = "builds"
+	collectorPermissions      = "permission_targets"
+	collectorUserDetails      = "user_details"
+	collectorAccessTokens     = "access_tokens"
+	collectorGroupDetails     = "group_details"
+	collectorGPGKeys          = "gpg_keys"
+)
+
+// CollectorStatus describes the outcome of the most recent runs of a collector.
+type CollectorStatus struct {
+	LastSuccess   *time.Time `json:"last_success,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorTime *time.Time `json:"last_error_time,omitempty"`
+	LastDuration  float64    `json:"last_duration_seconds"`
+	LastSkipped   int        `json:"last_skipped,omitempty"`
+}
+
+// Status is a snapshot of the exporter state served on the status endpoint.
+type Status struct {
+	Ready               bool                       `json:"ready"`
+	LastReadyCheck      *time.Time                 `json:"last_ready_check,omitempty"`
+	LastReadyError      string                     `json:"last_ready_error,omitempty"`
+	ArtifactoryVersion  string                     `json:"artifactory_version,omitempty"`
+	ArtifactoryRevision string                     `json:"artifactory_revision,omitempty"`
+	LastScrape          *time.Time                 `json:"last_scrape,omitempty"`
+	LastScrapeDuration  float64                    `json:"last_scrape_duration_seconds"`
+	Collectors          map[string]CollectorStatus `json:"collectors"`
+}
+
+// statusTracker records collector outcomes and readiness, independently of
+// the scrape mutex so status requests never wait for a running scrape.
+type statusTracker struct {
+	mutex          sync.RWMutex
+	checkInterval  time.Duration
+	checking       bool
+	lastReady      time.Time
+	lastReadyCheck time.Time
+	lastReadyError string
+	version        string
+	revision       string
+	lastScrape     time.Time
+	scrapeDuration time.Duration
+	collectors     map[string]CollectorStatus
+	// map[<collector>] <items skipped by the running collection>
+	skipped map[string]int
+}
+
+func newStatusTracker(checkInterval time.Duration) *statusTracker {
+	return &statusTracker{
+		checkInterval: checkInterval,
+		collectors:    make(map[string]CollectorStatus),
+		skipped:       make(map[string]int),
+	}
+}
+
+// skip adds items skipped by the running collection of a collector, they
+// are reported with its outcome.
+func (s *statusTracker) skip(collector string, count int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.skipped[collector] += count
+}
+
+// record stores the outcome and duration of a collector run.
+func (s *statusTracker) record(collector string, duration time.Duration, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	status := s.collectors[collector]
+	status.LastDuration = duration.Seconds()
+	status.LastSkipped = s.skipped[collector]
+	delete(s.skipped, collector)
+	if err != nil {
+		status.LastError = err.Error()
+		status.LastErrorTime = &now
+	} else {
+		status.LastSuccess = &now
+		status.LastError = ""
+		status.LastErrorTime = nil
+	}
+	s.collectors[collector] = status
+}
+
+// recordScrape stores the start time and duration of a whole scrape.
+func (s *statusTracker) recordScrape(start time.Time, duration time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.lastScrape = start
+	s.scrapeDuration = duration
+}
+
+// setVersion stores the Artifactory version detected during a scrape or readiness check.
+func (s *statusTracker) setVersion(version, revision string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.version = version
+	s.revision = revision
+}
+
+// recordReady stores the outcome of a readiness check. Successful scrapes
+// count as successful readiness checks too.
+func (s *statusTracker) recordReady(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	s.lastReadyCheck = now
+	if err != nil {
+		s.lastReadyError = err.Error()
+		return
+	}
+	s.lastReady = now
+	s.lastReadyError = ""
+}
+
+// ready reports whether the last readiness check or scrape succeeded.
+func (s *statusTracker) ready() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.isReady()
+}
+
+func (s *statusTracker) isReady() bool {
+	return !s.lastReady.IsZero() && s.lastReadyError == ""
+}
+
+// startReadyCheck reports whether a readiness check is due, i.e. none is
+// running and the last result is older than the check interval, and marks
+// it as running if so. The check must be ended with endReadyCheck.
+func (s *statusTracker) startReadyCheck() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.checking || (!s.lastReadyCheck.IsZero() && time.Since(s.lastReadyCheck) < s.checkInterval) {
+		return false
+	}
+	s.checking = true
+	return true
+}
+
+// endReadyCheck stores the outcome of a readiness check started by startReadyCheck.
+func (s *statusTracker) endReadyCheck(err error) {
+	s.recordReady(err)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.checking = false
+}
+
+func (s *statusTracker) snapshot() Status {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	status := Status{
+		Ready:               s.isReady(),
+		LastReadyError:      s.lastReadyError,
+		ArtifactoryVersion:  s.version,
+		ArtifactoryRevision: s.revision,
+		LastScrapeDuration:  s.scrapeDuration.Seconds(),
+		Collectors:          make(map[string]CollectorStatus, len(s.collectors)),
+	}
+	if !s.lastReadyCheck.IsZero() {
+		lastReadyCheck := s.lastReadyCheck
+		status.LastReadyCheck = &lastReadyCheck
+	}
+	if !s.lastScrape.IsZero() {
+		lastScrape := s.lastScrape
+		status.LastScrape = &lastScrape
+	}
+	for name, collectorStatus := range s.collectors {
+		status.Collectors[name] = collectorStatus
+	}
+	return status
+}
+
+// track runs a collection step and records its outcome and duration under the given collector name.
+func (e *Exporter) track(collector string, step func() error) error {
+	start := time.Now()
+	err := step()
+	e.status.record(collector, time.Since(start), err)
+	return err
+}
+
+// CheckReady reports whether the exporter can serve meaningful metrics.
+// Artifactory must answer the ping endpoint and accept the configured
+// credentials. The result of the last check or scrape is served for the
+// check interval. Once it elapsed, Artifactory is checked again in the
+// background and the probe waits for the check at most readyCheckWait.
+func (e *Exporter) CheckReady() bool {
+	if e.status.startReadyCheck() {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			e.status.endReadyCheck(e.checkReady())
+		}()
+		select {
+		case <-done:
+		case <-time.After(readyCheckWait):
+			e.logger.Debug("Readiness check still running, serving the last result")
+		}
+	}
+	return e.status.ready()
+}
+
+// checkReady checks that Artifactory is reachable and accepts the credentials.
+func (e *Exporter) checkReady() error {
+	e.logger.Debug("Running readiness check")
+	health, err := e.client.FetchHealth()
+	if err == nil && !health.Healthy {
+		err = errNotHealthy
+	}
+	if err == nil {
+		// system/version requires authentication, so it doubles as a credentials check.
+		var buildInfo artifactory.BuildInfo
+		buildInfo, err = e.client.FetchBuildInfo()
+		if err == nil {
+			e.status.setVersion(buildInfo.Version, buildInfo.Revision)
+		}
+	}
+	if err != nil {
+		e.logger.Warn(
+			"Readiness check failed",
+			"err", err.Error(),
+		)
+	}
+	return err
+}
+
+// Status returns a snapshot of the readiness and collector states.
+func (e *Exporter) Status() Status {
+	return e.status.snapshot()
+}
+
+// EnabledCollectors returns the names of the collectors run on each scrape,
+// followed by the scheduled collectors.
+func (e *Exporter) EnabledCollectors() []string {
+	optional := e.exporterRuntimeConfig.OptionalMetrics
+	collectors := []string{collectorSystem, collectorLicenses, collectorStorage}
+	if optional.OpenMetrics {
+		collectors = append(collectors, collectorOpenMetrics)
+	}
+	if optional.Artifacts {
+		collectors = append(collectors, collectorArtifacts)
+	}
+	if optional.FederationStatus {
+		collectors = append(collectors, collectorFederation)
+	}
+	if optional.Repositories {
+		collectors = append(collectors, collectorRepositories)
+	}
+	if optional.RemoteRepositories {
+		collectors = append(collectors, collectorRemoteRepos)
+	}
+	if optional.VirtualRepositories {
+		collectors = append(collectors, collectorVirtualRepos)
+	}
+	if optional.Projects {
+		collectors = append(collectors, collectorProjects)
+	}
+	if optional.AccessTokens {
+		collectors = append(collectors, collectorAccessTokens)
+	}
+	if optional.GPGKeys {
+		collectors = append(collectors, collectorGPGKeys)
+	}
+	if optional.AccessFederationValidate {
+		collectors = append(collectors, collectorAccessFederation)
+	}
+	if optional.BackgroundTasks {
+		collectors = append(collectors, collectorBackgroundTasks)
+	}
+	for _, s := range e.scheduled {
+		collectors = append(collectors, s.name)
+	}
+	return collectors
+}
+
+// CacheStats returns the statistics of the Artifactory API response cache.
+func (e *Exporter) CacheStats() artifactory.CacheStats {
+	return e.client.CacheStats()
+}
+
+// LastPayloads returns the redacted Artifactory API responses of the last scrape.
+func (e *Exporter) LastPayloads() []artifactory.Payload {
+	return e.client.LastPayloads()
+}
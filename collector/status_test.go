@@ -0,0 +1,166 @@
+package collector
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+// newTestExporter creates an exporter scraping the given Artifactory URI.
+func newTestExporter(t *testing.T, uri string) *Exporter {
+	t.Helper()
+	conf := &config.Config{
+		ArtiScrapeURI:         uri,
+		ArtiTimeout:           5 * time.Second,
+		ArtiAQLTimeout:        time.Minute,
+		ReadyCheckInterval:    time.Minute,
+		ExporterRuntimeConfig: &config.ExporterRuntimeConfig{},
+		Credentials: &config.Credentials{
+			AuthMethod: "userPass",
+			Username:   "test",
+			Password:   "test",
+		},
+		Logger: newTestLogger(),
+	}
+	e, err := NewExporter(conf)
+	if err != nil {
+		t.Fatalf("NewExporter() error = %v", err)
+	}
+	t.Cleanup(e.Close)
+	return e
+}
+
+func TestStatusTracker(t *testing.T) {
+	s := newStatusTracker(time.Minute)
+
+	if s.ready() {
+		t.Error("Expected tracker not to be ready before any check")
+	}
+
+	s.record(collectorSystem, time.Second, errors.New("boom"))
+	status := s.snapshot()
+	if status.Collectors[collectorSystem].LastError != "boom" {
+		t.Errorf("LastError = %q, want %q", status.Collectors[collectorSystem].LastError, "boom")
+	}
+	if status.Collectors[collectorSystem].LastSuccess != nil {
+		t.Error("Expected no LastSuccess after a failure")
+	}
+
+	s.record(collectorSystem, time.Second, nil)
+	status = s.snapshot()
+	if status.Collectors[collectorSystem].LastError != "" {
+		t.Errorf("Expected LastError to be cleared, got %q", status.Collectors[collectorSystem].LastError)
+	}
+	if status.Collectors[collectorSystem].LastSuccess == nil {
+		t.Error("Expected LastSuccess to be set")
+	}
+
+	s.recordReady(nil)
+	if !s.ready() {
+		t.Error("Expected tracker to be ready after a successful check")
+	}
+
+	if s.startReadyCheck() {
+		t.Error("Expected no readiness check within the check interval")
+	}
+	s.lastReadyCheck = time.Now().Add(-2 * time.Minute)
+	if !s.startReadyCheck() {
+		t.Fatal("Expected a readiness check once the check interval elapsed")
+	}
+	if s.startReadyCheck() {
+		t.Error("Expected no concurrent readiness checks")
+	}
+	s.endReadyCheck(errors.New("unauthorized"))
+	if s.ready() {
+		t.Error("Expected tracker not to be ready after a failed check")
+	}
+}
+
+func TestCheckReady(t *testing.T) {
+	tests := []struct {
+		name          string
+		versionStatus int
+		expectReady   bool
+	}{
+		{"Reachable and authenticated", http.StatusOK, true},
+		{"Invalid credentials", http.StatusUnauthorized, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/system/ping":
+					w.Write([]byte("OK"))
+				case "/api/system/version":
+					w.WriteHeader(tt.versionStatus)
+					if tt.versionStatus == http.StatusOK {
+						w.Write([]byte(`{"version":"7.77.3","revision":"77703900"}`))
+					} else {
+						w.Write([]byte(`{"errors":[{"status":401,"message":"Bad credentials"}]}`))
+					}
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			e := newTestExporter(t, server.URL)
+			if got := e.CheckReady(); got != tt.expectReady {
+				t.Errorf("CheckReady() = %v, want %v", got, tt.expectReady)
+			}
+
+			status := e.Status()
+			if status.Ready != tt.expectReady {
+				t.Errorf("Status().Ready = %v, want %v", status.Ready, tt.expectReady)
+			}
+			if tt.expectReady && status.ArtifactoryVersion != "7.77.3" {
+				t.Errorf("Status().ArtifactoryVersion = %q, want %q", status.ArtifactoryVersion, "7.77.3")
+			}
+			if !tt.expectReady && status.LastReadyError == "" {
+				t.Error("Expected LastReadyError to be set")
+			}
+		})
+	}
+}
+
+func TestCheckReadyServesLastResult(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	var slow atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if slow.Load() {
+			<-release
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"errors":[{"status":401,"message":"Bad credentials"}]}`))
+	}))
+	defer server.Close()
+	defer close(release)
+	e := newTestExporter(t, server.URL)
+
+	if e.CheckReady() {
+		t.Fatal("Expected CheckReady() to fail with invalid credentials")
+	}
+	checked := requests.Load()
+	if e.CheckReady() || requests.Load() != checked {
+		t.Error("Expected the failed result to be served without checking Artifactory again")
+	}
+
+	// A check hanging on Artifactory does not hold the probe
+	e.status.lastReadyCheck = time.Now().Add(-2 * time.Minute)
+	slow.Store(true)
+	start := time.Now()
+	if e.CheckReady() {
+		t.Error("Expected the last result to be served while the check is running")
+	}
+	if elapsed := time.Since(start); elapsed > 2*readyCheckWait {
+		t.Errorf("CheckReady() took %v, want at most %v", elapsed, readyCheckWait)
+	}
+}
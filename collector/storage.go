@@ -2,6 +2,7 @@ package collector
 
 import (
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -62,10 +63,6 @@ func (e *Exporter) exportFilestore(metricName string, metric *prometheus.Desc, s
 		return
 	}
 	value, percent, err := e.convArtiToPromFileStoreData(size)
-	/*
-	 * What should you use the percentage for?
-	 * Maybe Issue #126?
-	 */
 	if err != nil {
 		e.jsonParseFailures.Inc()
 		e.logger.Warn(
@@ -84,10 +81,43 @@ func (e *Exporter) exportFilestore(metricName string, metric *prometheus.Desc, s
 	ch <- prometheus.MustNewConstMetric(metric, prometheus.GaugeValue, value, fileStoreType, fileStoreDir, nodeId)
 }
 
+// exportFilestoreUsage exports the usage percentage Artifactory reports next
+// to the used space (e.g. "32.22 GB (15.77%)") as a ratio between 0 and 1.
+// Stores reporting no percentage (e.g. object storage) are skipped.
+func (e *Exporter) exportFilestoreUsage(metricName string, metric *prometheus.Desc, usedSpace string, fileStoreType string, fileStoreDir string, nodeId string, ch chan<- prometheus.Metric) {
+	if !strings.Contains(usedSpace, `(`) || strings.Contains(usedSpace, `(N/A)`) {
+		e.logger.Debug(
+			"No usage percentage reported for the file store",
+			"storage_type", fileStoreType,
+			"storage_dir", fileStoreDir,
+		)
+		return
+	}
+	_, percent, err := e.convArtiToPromFileStoreData(usedSpace)
+	if err != nil {
+		e.jsonParseFailures.Inc()
+		e.logger.Warn(
+			msgErrCalcVal,
+			"metric", metricName,
+			"err", err.Error(),
+		)
+		return
+	}
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", metricName,
+		"value", percent,
+	)
+	ch <- prometheus.MustNewConstMetric(metric, prometheus.GaugeValue, percent, fileStoreType, fileStoreDir, nodeId)
+}
+
 type RepoArtifactsSummary struct {
 	period          string // 30s 1m 15m 2h
 	TotalCreated    float64
 	TotalDownloaded float64
+	CreatedBytes    float64
+	Downloads       float64
+	DownloadedBytes float64
 }
 
 type repoSummary struct {
@@ -214,8 +244,6 @@ func (e *Exporter) exportRepo(repoSummaries []repoSummary, ch chan<- prometheus.
 }
 
 func (e *Exporter) exportStorage(storageInfo artifactory.StorageInfo, ch chan<- prometheus.Metric) {
-	fileStoreType := strings.ToLower(storageInfo.FileStoreSummary.StorageType)
-	fileStoreDir := storageInfo.FileStoreSummary.StorageDirectory
 	for metricName, metric := range storageMetrics {
 		switch metricName {
 		case "artifacts":
@@ -226,14 +254,62 @@ func (e *Exporter) exportStorage(storageInfo artifactory.StorageInfo, ch chan<-
 			e.exportCount(metricName, metric, storageInfo.BinariesSummary.BinariesCount, storageInfo.NodeId, ch)
 		case "binariesSize":
 			e.exportSize(metricName, metric, storageInfo.BinariesSummary.BinariesSize, storageInfo.NodeId, ch)
-		case "filestore":
-			e.exportFilestore(metricName, metric, storageInfo.FileStoreSummary.TotalSpace, fileStoreType, fileStoreDir, storageInfo.NodeId, ch)
-		case "filestoreUsed":
-			e.exportFilestore(metricName, metric, storageInfo.FileStoreSummary.UsedSpace, fileStoreType, fileStoreDir, storageInfo.NodeId, ch)
-		case "filestoreFree":
-			e.exportFilestore(metricName, metric, storageInfo.FileStoreSummary.FreeSpace, fileStoreType, fileStoreDir, storageInfo.NodeId, ch)
 		case "items":
 			e.exportCount(metricName, metric, storageInfo.BinariesSummary.ItemsCount, storageInfo.NodeId, ch)
 		}
 	}
+	e.exportFilestores(storageInfo, ch)
+}
+
+// exportFilestores exports the space of every binary store reported by storageinfo.
+// Each store is identified by its storage type and directory.
+func (e *Exporter) exportFilestores(storageInfo artifactory.StorageInfo, ch chan<- prometheus.Metric) {
+	seen := make(map[[2]string]bool, len(storageInfo.FileStoreSummaries))
+	for _, store := range storageInfo.FileStoreSummaries {
+		fileStoreType := strings.ToLower(store.StorageType)
+		fileStoreDir := store.StorageDirectory
+		key := [2]string{fileStoreType, fileStoreDir}
+		if seen[key] {
+			e.logger.Warn(
+				"Skipping file store reported more than once",
+				"storage_type", fileStoreType,
+				"storage_dir", fileStoreDir,
+			)
+			continue
+		}
+		seen[key] = true
+		for metricName, metric := range storageMetrics {
+			switch metricName {
+			case "filestore":
+				e.exportFilestore(metricName, metric, store.TotalSpace, fileStoreType, fileStoreDir, storageInfo.NodeId, ch)
+			case "filestoreUsed":
+				e.exportFilestore(metricName, metric, store.UsedSpace, fileStoreType, fileStoreDir, storageInfo.NodeId, ch)
+			case "filestoreFree":
+				e.exportFilestore(metricName, metric, store.FreeSpace, fileStoreType, fileStoreDir, storageInfo.NodeId, ch)
+			case "filestoreUsedRatio":
+				e.exportFilestoreUsage(metricName, metric, store.UsedSpace, fileStoreType, fileStoreDir, storageInfo.NodeId, ch)
+			}
+		}
+	}
+}
+
+// exportStorageInfoFreshness exports when the storage summary was last recalculated.
+// Timestamps are only exported once known, the age only once a calculation completed.
+func (e *Exporter) exportStorageInfoFreshness(nodeId string, ch chan<- prometheus.Metric) {
+	freshness := e.client.StorageInfoFreshness()
+	if !freshness.Requested.IsZero() {
+		ch <- prometheus.MustNewConstMetric(storageInfoFreshnessMetrics["requested"], prometheus.GaugeValue, float64(freshness.Requested.Unix()), nodeId)
+	}
+	if freshness.Completed.IsZero() {
+		e.logger.Debug("No storage info calculation completed yet")
+		return
+	}
+	age := time.Since(freshness.Completed).Seconds()
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "storageInfoAge",
+		"value", age,
+	)
+	ch <- prometheus.MustNewConstMetric(storageInfoFreshnessMetrics["completed"], prometheus.GaugeValue, float64(freshness.Completed.Unix()), nodeId)
+	ch <- prometheus.MustNewConstMetric(storageInfoFreshnessMetrics["age"], prometheus.GaugeValue, age, nodeId)
 }
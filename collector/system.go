@@ -25,6 +25,7 @@ func (e *Exporter) exportSystem(ch chan<- prometheus.Metric) error {
 		e.totalAPIErrors.Inc()
 		return err
 	}
+	e.status.setVersion(buildInfo.Version, buildInfo.Revision)
 	licenseInfo, err := e.client.FetchLicense()
 	if err != nil {
 		e.logger.Error(
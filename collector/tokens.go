@@ -0,0 +1,100 @@
+package collector
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// accessTokenSummary counts the access tokens for the token inventory metrics.
+type accessTokenSummary struct {
+	bySubjectType map[string]float64
+	withoutExpiry float64
+	expiring      map[int]float64
+	refreshable   map[bool]float64
+}
+
+// tokenSubjectType returns the type of the subject of a token. User and group
+// subjects are <service id>/users/<name> and <service id>/groups/<name>,
+// subjects without a path are services.
+func tokenSubjectType(subject string) string {
+	parts := strings.Split(subject, "/")
+	if len(parts) < 3 {
+		return "service"
+	}
+	switch kind := parts[len(parts)-2]; kind {
+	case "users":
+		return "user"
+	case "groups":
+		return "group"
+	default:
+		return kind
+	}
+}
+
+// summarizeAccessTokens counts the tokens by subject type, without
+// expiry, expiring within each of expiryDays and by whether they are refreshable.
+func summarizeAccessTokens(tokens []artifactory.AccessToken, now time.Time, expiryDays []int) accessTokenSummary {
+	summary := accessTokenSummary{
+		bySubjectType: map[string]float64{},
+		expiring:      map[int]float64{},
+		refreshable:   map[bool]float64{true: 0, false: 0},
+	}
+	for _, days := range expiryDays {
+		summary.expiring[days] = 0
+	}
+	for _, token := range tokens {
+		summary.bySubjectType[tokenSubjectType(token.Subject)]++
+		summary.refreshable[token.Refreshable]++
+		if token.Expiry <= 0 {
+			summary.withoutExpiry++
+			continue
+		}
+		expiry := time.Unix(token.Expiry, 0)
+		if expiry.Before(now) {
+			continue
+		}
+		for _, days := range expiryDays {
+			if expiry.Before(now.AddDate(0, 0, days)) {
+				summary.expiring[days]++
+			}
+		}
+	}
+	return summary
+}
+
+// exportAccessTokens exports the inventory of the access tokens listed by JFrog Access.
+func (e *Exporter) exportAccessTokens(ch chan<- prometheus.Metric) error {
+	tokens, err := e.client.FetchAccessTokens()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching access tokens",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return err
+	}
+
+	summary := summarizeAccessTokens(tokens.Tokens, time.Now(), e.exporterRuntimeConfig.AccessTokenExpiryDays)
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "accessTokens",
+		"value", len(tokens.Tokens),
+		"withoutExpiry", summary.withoutExpiry,
+	)
+	for subjectType, count := range summary.bySubjectType {
+		ch <- prometheus.MustNewConstMetric(accessTokenMetrics["tokens"], prometheus.GaugeValue, count, subjectType, tokens.NodeId)
+	}
+	ch <- prometheus.MustNewConstMetric(accessTokenMetrics["withoutExpiry"], prometheus.GaugeValue, summary.withoutExpiry, tokens.NodeId)
+	for days, count := range summary.expiring {
+		ch <- prometheus.MustNewConstMetric(accessTokenMetrics["expiring"], prometheus.GaugeValue, count, strconv.Itoa(days), tokens.NodeId)
+	}
+	for refreshable, count := range summary.refreshable {
+		ch <- prometheus.MustNewConstMetric(accessTokenMetrics["refreshable"], prometheus.GaugeValue, count, strconv.FormatBool(refreshable), tokens.NodeId)
+	}
+	return nil
+}
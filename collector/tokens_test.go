@@ -0,0 +1,86 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+func TestSummarizeAccessTokens(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := int64(24 * 3600)
+	tokens := []artifactory.AccessToken{
+		{Subject: "jfac@01abc/users/alice", Expiry: now.Unix() + 3*day, Refreshable: true},
+		{Subject: "jfac@01abc/users/ci"},
+		{Subject: "jfac@01abc/groups/readers", Expiry: now.Unix() + 20*day},
+		{Subject: "jfrt@01abc", Expiry: now.Unix() - day},
+	}
+
+	summary := summarizeAccessTokens(tokens, now, []int{7, 30})
+	want := map[string]float64{"user": 2, "group": 1, "service": 1}
+	for subjectType, count := range want {
+		if summary.bySubjectType[subjectType] != count {
+			t.Errorf("tokens %s = %v, want %v", subjectType, summary.bySubjectType[subjectType], count)
+		}
+	}
+	if summary.withoutExpiry != 1 {
+		t.Errorf("without expiry = %v, want 1", summary.withoutExpiry)
+	}
+	if summary.expiring[7] != 1 || summary.expiring[30] != 2 {
+		t.Errorf("expiring = %v, want 7: 1, 30: 2", summary.expiring)
+	}
+	if summary.refreshable[true] != 1 || summary.refreshable[false] != 3 {
+		t.Errorf("refreshable = %v, want true: 1, false: 3", summary.refreshable)
+	}
+}
+
+func TestExportAccessTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/system/ping":
+			w.Write([]byte("OK"))
+		case "/access/api/v1/tokens":
+			// Documented list response, which has no scope
+			w.Write([]byte(`{"tokens":[
+				{"token_id":"a1b2","subject":"jfac@01abc/users/alice","expiry":0,"issued_at":1717200000,"refreshable":true,"description":"ci"},
+				{"token_id":"c3d4","subject":"jfrt@01abc","expiry":1717286400,"issued_at":1717200000,"refreshable":false,"description":""}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+
+	ch := make(chan prometheus.Metric, 10)
+	if err := e.exportAccessTokens(ch); err != nil {
+		t.Fatalf("exportAccessTokens() error = %v", err)
+	}
+	close(ch)
+	tokens := map[string]float64{}
+	withoutExpiry := -1.0
+	for m := range ch {
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		switch m.Desc() {
+		case accessTokenMetrics["tokens"]:
+			tokens[labelValue(&metric, "subject_type")] = metric.GetGauge().GetValue()
+		case accessTokenMetrics["withoutExpiry"]:
+			withoutExpiry = metric.GetGauge().GetValue()
+		}
+	}
+	if len(tokens) != 2 || tokens["user"] != 1 || tokens["service"] != 1 {
+		t.Errorf("tokens = %v, want user: 1, service: 1", tokens)
+	}
+	if withoutExpiry != 1 {
+		t.Errorf("without expiry = %v, want 1", withoutExpiry)
+	}
+}
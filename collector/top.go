@@ -0,0 +1,284 @@
+package collector
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// maxTopArtifactsSeries bounds the number of series of each top artifacts metric.
+	maxTopArtifactsSeries = 1000
+	// maxTopArtifactsLabelLength bounds the length of the path and name labels.
+	maxTopArtifactsLabelLength = 256
+)
+
+// TopArtifact is an artifact of the top artifacts report.
+type TopArtifact struct {
+	Repo      string  `json:"repo"`
+	Path      string  `json:"path"`
+	Name      string  `json:"name"`
+	Size      float64 `json:"size_bytes"`
+	Downloads float64 `json:"downloads,omitempty"`
+}
+
+// TopArtifactsReport lists the largest and most downloaded artifacts, of the
+// whole instance or of each repository.
+type TopArtifactsReport struct {
+	GeneratedAt    *time.Time    `json:"generated_at,omitempty"`
+	PerRepository  bool          `json:"per_repository"`
+	Largest        []TopArtifact `json:"largest"`
+	MostDownloaded []TopArtifact `json:"most_downloaded"`
+}
+
+// topArtifactsStore keeps the last top artifacts report for the JSON endpoint.
+type topArtifactsStore struct {
+	mutex  sync.RWMutex
+	report TopArtifactsReport
+}
+
+// topDownloads keeps the count most downloaded artifacts added to it. It is a
+// min-heap on the downloads, so the least downloaded artifact kept is the
+// first one replaced.
+type topDownloads struct {
+	count     int
+	artifacts []TopArtifact
+}
+
+func (t *topDownloads) Len() int {
+	return len(t.artifacts)
+}
+
+func (t *topDownloads) Less(i, j int) bool {
+	return t.artifacts[i].Downloads < t.artifacts[j].Downloads
+}
+
+func (t *topDownloads) Swap(i, j int) {
+	t.artifacts[i], t.artifacts[j] = t.artifacts[j], t.artifacts[i]
+}
+
+func (t *topDownloads) Push(x any) {
+	t.artifacts = append(t.artifacts, x.(TopArtifact))
+}
+
+func (t *topDownloads) Pop() any {
+	last := t.artifacts[len(t.artifacts)-1]
+	t.artifacts = t.artifacts[:len(t.artifacts)-1]
+	return last
+}
+
+// add keeps a if it is among the count most downloaded artifacts added.
+func (t *topDownloads) add(a TopArtifact) {
+	if len(t.artifacts) < t.count {
+		heap.Push(t, a)
+		return
+	}
+	if t.count > 0 && a.Downloads > t.artifacts[0].Downloads {
+		t.artifacts[0] = a
+		heap.Fix(t, 0)
+	}
+}
+
+// sorted returns the artifacts kept, the most downloaded first.
+func (t *topDownloads) sorted() []TopArtifact {
+	artifacts := slices.Clone(t.artifacts)
+	sort.Slice(artifacts, func(i, j int) bool {
+		if artifacts[i].Downloads != artifacts[j].Downloads {
+			return artifacts[i].Downloads > artifacts[j].Downloads
+		}
+		return artifacts[i].Repo+"/"+artifacts[i].Path+"/"+artifacts[i].Name < artifacts[j].Repo+"/"+artifacts[j].Path+"/"+artifacts[j].Name
+	})
+	return artifacts
+}
+
+// newTopArtifactsCollector returns a scheduled collector searching the
+// largest and most downloaded artifacts.
+func (e *Exporter) newTopArtifactsCollector() *scheduledCollector {
+	return &scheduledCollector{
+		name:     collectorTopArtifacts,
+		interval: e.exporterRuntimeConfig.TopArtifactsInterval,
+		descs:    []*prometheus.Desc{topArtifactsMetrics["size"], topArtifactsMetrics["downloads"]},
+		collect:  e.exportTopArtifacts,
+	}
+}
+
+func (e *Exporter) exportTopArtifacts(ctx context.Context, ch chan<- prometheus.Metric) error {
+	count := e.exporterRuntimeConfig.TopArtifactsCount
+	report := TopArtifactsReport{PerRepository: e.exporterRuntimeConfig.TopArtifactsPerRepo}
+	var nodeId string
+
+	// An empty criteria and repository key stand for the whole instance
+	criterias := []string{""}
+	var keys []string
+	if report.PerRepository {
+		repositories, id, err := e.fetchItemRepositories()
+		if err != nil {
+			return err
+		}
+		nodeId = id
+		criterias = criterias[:0]
+		for _, repo := range repositories {
+			criterias = append(criterias, fmt.Sprintf(", \"repo\" : %q", repo.key))
+			keys = append(keys, repo.key)
+		}
+	}
+	for _, criteria := range criterias {
+		largest, id, err := e.findLargestArtifacts(ctx, fmt.Sprintf("{\"type\" : \"file\"%s}", criteria), count)
+		if err != nil {
+			return err
+		}
+		if nodeId == "" {
+			nodeId = id
+		}
+		report.Largest = append(report.Largest, largest...)
+	}
+	mostDownloaded, err := e.findMostDownloadedArtifacts(ctx, keys, count)
+	if err != nil {
+		return err
+	}
+	report.MostDownloaded = mostDownloaded
+
+	e.exportTopArtifactsList("size", report.Largest, func(a TopArtifact) float64 { return a.Size }, nodeId, ch)
+	e.exportTopArtifactsList("downloads", report.MostDownloaded, func(a TopArtifact) float64 { return a.Downloads }, nodeId, ch)
+
+	now := time.Now()
+	report.GeneratedAt = &now
+	e.topArtifacts.mutex.Lock()
+	e.topArtifacts.report = report
+	e.topArtifacts.mutex.Unlock()
+	return nil
+}
+
+// findLargestArtifacts returns the count largest items matching the criteria.
+// Only item fields are included, so that Artifactory applies the sort and limit.
+func (e *Exporter) findLargestArtifacts(ctx context.Context, criteria string, count int) ([]TopArtifact, string, error) {
+	e.logger.Debug(
+		"Finding largest artifacts",
+		"criteria", criteria,
+	)
+	query := fmt.Sprintf("items.find(%s).include(\"repo\", \"path\", \"name\", \"size\").sort({\"$desc\" : [\"size\"]}).limit(%d)", criteria, count)
+	artifacts := make([]TopArtifact, 0, count)
+	_, nodeId, err := e.streamAQL(ctx, query, func(dec *json.Decoder) error {
+		var item artifactResult
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		artifacts = append(artifacts, TopArtifact{
+			Repo: item.Repo,
+			Path: item.Path,
+			Name: item.Name,
+			Size: item.Size,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return artifacts, nodeId, nil
+}
+
+// findMostDownloadedArtifacts returns the count most downloaded files of the
+// whole instance, or of each of the repositories with the given keys. AQL can
+// neither sort on the download statistics nor sort and limit a query
+// including them, so all downloaded files are streamed by a single query and
+// only the count most downloaded ones are kept.
+func (e *Exporter) findMostDownloadedArtifacts(ctx context.Context, keys []string, count int) ([]TopArtifact, error) {
+	e.logger.Debug("Finding most downloaded artifacts")
+	tops := map[string]*topDownloads{}
+	if keys == nil {
+		tops[""] = &topDownloads{count: count}
+	}
+	for _, key := range keys {
+		tops[key] = &topDownloads{count: count}
+	}
+	query := "items.find({\"type\" : \"file\", \"stat.downloads\" : {\"$gt\" : 0}}).include(\"repo\", \"path\", \"name\", \"size\", \"stat.downloads\")"
+	_, _, err := e.streamAQL(ctx, query, func(dec *json.Decoder) error {
+		var item artifactResult
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		key := ""
+		if keys != nil {
+			key = item.Repo
+		}
+		// Files of other repositories than the given ones are left out
+		if top, exists := tops[key]; exists {
+			top.add(TopArtifact{
+				Repo:      item.Repo,
+				Path:      item.Path,
+				Name:      item.Name,
+				Size:      item.Size,
+				Downloads: item.downloads(),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		return tops[""].sorted(), nil
+	}
+	var artifacts []TopArtifact
+	for _, key := range keys {
+		artifacts = append(artifacts, tops[key].sorted()...)
+	}
+	return artifacts, nil
+}
+
+// exportTopArtifactsList exports a list of top artifacts, with long paths and
+// names truncated and at most maxTopArtifactsSeries series.
+func (e *Exporter) exportTopArtifactsList(metricName string, artifacts []TopArtifact, value func(TopArtifact) float64, nodeId string, ch chan<- prometheus.Metric) {
+	if len(artifacts) > maxTopArtifactsSeries {
+		e.logger.Warn(
+			"Too many top artifacts, exporting only the first ones",
+			"metric", metricName,
+			"artifacts", len(artifacts),
+			"limit", maxTopArtifactsSeries,
+			"per_repository", e.exporterRuntimeConfig.TopArtifactsPerRepo,
+		)
+		artifacts = artifacts[:maxTopArtifactsSeries]
+	}
+	seen := make(map[[3]string]bool, len(artifacts))
+	for _, a := range artifacts {
+		labels := [3]string{a.Repo, truncateLabel(a.Path, maxTopArtifactsLabelLength), truncateLabel(a.Name, maxTopArtifactsLabelLength)}
+		if seen[labels] {
+			continue
+		}
+		seen[labels] = true
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", metricName,
+			"repo", a.Repo,
+			"path", a.Path,
+			"name", a.Name,
+			"value", value(a),
+		)
+		ch <- prometheus.MustNewConstMetric(topArtifactsMetrics[metricName], prometheus.GaugeValue, value(a), labels[0], labels[1], labels[2], nodeId)
+	}
+}
+
+// truncateLabel shortens a label value to at most limit bytes, without splitting a UTF-8 character.
+func truncateLabel(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	for limit > 0 && value[limit]&0xC0 == 0x80 {
+		limit--
+	}
+	return value[:limit] + "…"
+}
+
+// TopArtifacts returns the last top artifacts report.
+func (e *Exporter) TopArtifacts() TopArtifactsReport {
+	e.topArtifacts.mutex.RLock()
+	defer e.topArtifacts.mutex.RUnlock()
+	return e.topArtifacts.report
+}
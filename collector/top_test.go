@@ -0,0 +1,112 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestExportTopArtifacts(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/repositories":
+			w.Write([]byte(`[
+				{"key":"libs-release","type":"LOCAL","packageType":"Maven"},
+				{"key":"maven","type":"VIRTUAL","packageType":"Maven"}
+			]`))
+		case "/api/search/aql":
+			body, _ := io.ReadAll(r.Body)
+			queries = append(queries, string(body))
+			if strings.Contains(string(body), "stat.") {
+				// Artifactory can't sort on stat fields, nor sort and limit a query including them
+				if strings.Contains(string(body), ".sort(") || strings.Contains(string(body), ".limit(") {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"errors":[{"status":400,"message":"sort and limit need primary domain fields only"}]}`))
+					return
+				}
+				w.Write([]byte(`{"results":[
+					{"repo":"libs-release","path":"org/a","name":"a.jar","size":1,"stats":[{"downloads":5}]},
+					{"repo":"libs-release","path":"org/b","name":"b.jar","size":1,"stats":[{"downloads":50}]},
+					{"repo":"libs-release","path":"org/c","name":"c.jar","size":1,"stats":[{"downloads":2}]},
+					{"repo":"other","path":"org/d","name":"d.jar","size":1,"stats":[{"downloads":500}]}
+				]}`))
+				return
+			}
+			w.Write([]byte(`{"results":[
+				{"repo":"libs-release","path":"org/big","name":"big.jar","size":1000},
+				{"repo":"libs-release","path":"org/small","name":"small.jar","size":10}
+			],"range":{"start_pos":0,"end_pos":2,"total":2,"limit":2}}`))
+		}
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+	e.exporterRuntimeConfig.TopArtifactsCount = 2
+	e.exporterRuntimeConfig.TopArtifactsPerRepo = true
+
+	ch := make(chan prometheus.Metric, 10)
+	if err := e.exportTopArtifacts(context.Background(), ch); err != nil {
+		t.Fatalf("exportTopArtifacts() error = %v", err)
+	}
+	close(ch)
+
+	if len(queries) != 2 {
+		t.Fatalf("Expected a query for the local repository and one for the downloads, got %d: %v", len(queries), queries)
+	}
+	if !strings.Contains(queries[0], `"repo" : "libs-release"`) || !strings.Contains(queries[0], `.sort({"$desc" : ["size"]}).limit(2)`) {
+		t.Errorf("Unexpected query %s", queries[0])
+	}
+	if n := len(ch); n != 4 {
+		t.Errorf("Expected 4 metrics, got %d", n)
+	}
+
+	report := e.TopArtifacts()
+	if report.GeneratedAt == nil || !report.PerRepository {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if len(report.Largest) != 2 || report.Largest[0].Name != "big.jar" || report.Largest[1].Size != 10 {
+		t.Errorf("Unexpected report lists %+v", report)
+	}
+	// Only the most downloaded files of the repository are kept
+	if len(report.MostDownloaded) != 2 || report.MostDownloaded[0].Downloads != 50 || report.MostDownloaded[1].Name != "a.jar" {
+		t.Errorf("Unexpected most downloaded %+v", report.MostDownloaded)
+	}
+}
+
+func TestTopDownloads(t *testing.T) {
+	top := &topDownloads{count: 3}
+	for _, downloads := range []float64{4, 9, 1, 7, 3, 9, 2} {
+		top.add(TopArtifact{Name: fmt.Sprintf("%v.jar", downloads), Downloads: downloads})
+	}
+	var got []float64
+	for _, a := range top.sorted() {
+		got = append(got, a.Downloads)
+	}
+	if want := []float64{9, 9, 7}; !slices.Equal(got, want) {
+		t.Errorf("sorted() downloads = %v, want %v", got, want)
+	}
+}
+
+func TestTruncateLabel(t *testing.T) {
+	tests := []struct {
+		value string
+		limit int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"abcdefghij", 4, "abcd…"},
+		{"aé", 2, "a…"},
+	}
+	for _, tt := range tests {
+		if got := truncateLabel(tt.value, tt.limit); got != tt.want {
+			t.Errorf("truncateLabel(%q, %d) = %q, want %q", tt.value, tt.limit, got, tt.want)
+		}
+	}
+}
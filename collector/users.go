@@ -0,0 +1,128 @@
+package collector
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+// userSummary counts the users by activity and account state.
+type userSummary struct {
+	admins           float64
+	locked           float64
+	disabled         float64
+	inactive         map[int]float64
+	passwordExpiring float64
+}
+
+// summarizeUsers counts the admin, locked and disabled users, the users who
+// did not log in within each of inactiveDays and the users whose password
+// expires within expiryDays. Users who never logged in are inactive.
+func summarizeUsers(users []artifactory.UserDetails, lockedUsers []string, now time.Time, inactiveDays []int, expiryDays int) userSummary {
+	summary := userSummary{inactive: map[int]float64{}}
+	for _, days := range inactiveDays {
+		summary.inactive[days] = 0
+	}
+	locked := map[string]bool{}
+	for _, name := range lockedUsers {
+		locked[name] = true
+	}
+	expiryLimit := now.AddDate(0, 0, expiryDays)
+
+	for _, user := range users {
+		if user.Admin {
+			summary.admins++
+		}
+		if locked[user.Name] || user.Status == "locked" {
+			summary.locked++
+		}
+		if user.Status == "disabled" {
+			summary.disabled++
+		}
+		lastLoggedIn, err := time.Parse(time.RFC3339, user.LastLoggedIn)
+		for _, days := range inactiveDays {
+			if err != nil || lastLoggedIn.Before(now.AddDate(0, 0, -days)) {
+				summary.inactive[days]++
+			}
+		}
+		if passwordExpiration, err := time.Parse(time.RFC3339, user.PasswordExpiration); err == nil && passwordExpiration.Before(expiryLimit) {
+			summary.passwordExpiring++
+		}
+	}
+	return summary
+}
+
+// newUserDetailsCollector returns a scheduled collector fetching the details
+// of every user.
+func (e *Exporter) newUserDetailsCollector() *scheduledCollector {
+	return &scheduledCollector{
+		name:     collectorUserDetails,
+		interval: e.exporterRuntimeConfig.UserDetailsInterval,
+		descs: []*prometheus.Desc{
+			userDetailsMetrics["admins"],
+			userDetailsMetrics["locked"],
+			userDetailsMetrics["disabled"],
+			userDetailsMetrics["inactive"],
+			userDetailsMetrics["passwordExpiring"],
+		},
+		collect: e.exportUserDetails,
+	}
+}
+
+// exportUserDetails exports the user activity and account state metrics. The
+// details of each user take one API call.
+func (e *Exporter) exportUserDetails(ctx context.Context, ch chan<- prometheus.Metric) error {
+	users, err := e.client.FetchUsers()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching security/users",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return err
+	}
+	lockedUsers, err := e.client.FetchLockedUsers()
+	if err != nil {
+		e.logger.Error(
+			"Couldn't scrape Artifactory when fetching security/lockedUsers",
+			"err", err.Error(),
+		)
+		e.totalAPIErrors.Inc()
+		return err
+	}
+
+	fetchedDetails := fetchEach(ctx, e, collectorUserDetails, users.Users,
+		func(user artifactory.User) string { return user.Name },
+		func(user artifactory.User) (artifactory.UserDetails, error) {
+			return e.client.FetchUserDetails(user.Name)
+		},
+	)
+	details := make([]artifactory.UserDetails, 0, len(fetchedDetails))
+	for _, userDetails := range fetchedDetails {
+		details = append(details, userDetails.value)
+	}
+
+	inactiveDays := e.exporterRuntimeConfig.UserInactiveDays
+	expiryDays := e.exporterRuntimeConfig.UserPasswordExpiryDays
+	summary := summarizeUsers(details, lockedUsers, time.Now(), inactiveDays, expiryDays)
+	e.logger.Debug(
+		logDbgMsgRegMetric,
+		"metric", "userDetails",
+		"users", len(details),
+		"admins", summary.admins,
+		"locked", summary.locked,
+		"disabled", summary.disabled,
+	)
+	ch <- prometheus.MustNewConstMetric(userDetailsMetrics["admins"], prometheus.GaugeValue, summary.admins, users.NodeId)
+	ch <- prometheus.MustNewConstMetric(userDetailsMetrics["locked"], prometheus.GaugeValue, summary.locked, users.NodeId)
+	ch <- prometheus.MustNewConstMetric(userDetailsMetrics["disabled"], prometheus.GaugeValue, summary.disabled, users.NodeId)
+	for days, count := range summary.inactive {
+		ch <- prometheus.MustNewConstMetric(userDetailsMetrics["inactive"], prometheus.GaugeValue, count, strconv.Itoa(days), users.NodeId)
+	}
+	ch <- prometheus.MustNewConstMetric(userDetailsMetrics["passwordExpiring"], prometheus.GaugeValue, summary.passwordExpiring, strconv.Itoa(expiryDays), users.NodeId)
+	return nil
+}
@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+)
+
+func TestSummarizeUsers(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	users := []artifactory.UserDetails{
+		{Name: "admin", Admin: true, Status: "enabled", LastLoggedIn: "2024-05-31T10:00:00.000Z"},
+		{Name: "alice", Status: "enabled", LastLoggedIn: "2024-04-01T10:00:00.000Z", PasswordExpiration: "2024-06-10T00:00:00.000Z"},
+		{Name: "bob", Status: "disabled", LastLoggedIn: "2023-01-01T10:00:00.000Z", PasswordExpiration: "2024-05-01T00:00:00.000Z"},
+		{Name: "ci", Status: "enabled", PasswordExpiration: "2024-09-01T00:00:00.000Z"},
+	}
+
+	summary := summarizeUsers(users, []string{"alice"}, now, []int{30, 90}, 14)
+	if summary.admins != 1 || summary.locked != 1 || summary.disabled != 1 {
+		t.Errorf("admins = %v, locked = %v, disabled = %v, want 1, 1, 1", summary.admins, summary.locked, summary.disabled)
+	}
+	if summary.inactive[30] != 3 || summary.inactive[90] != 2 {
+		t.Errorf("inactive = %v, want 30: 3, 90: 2", summary.inactive)
+	}
+	if summary.passwordExpiring != 2 {
+		t.Errorf("password expiring = %v, want 2", summary.passwordExpiring)
+	}
+}
+
+func TestExportUserDetailsSkipsFailedUsers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/security/users":
+			w.Write([]byte(`[{"name":"admin","realm":"internal"},{"name":"removed","realm":"internal"}]`))
+		case "/api/security/lockedUsers":
+			w.Write([]byte(`[]`))
+		case "/access/api/v2/users/admin":
+			w.Write([]byte(`{"username":"admin","admin":true,"status":"enabled"}`))
+		default:
+			// removed was deleted since the users were listed
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+	e.exporterRuntimeConfig.UserPasswordExpiryDays = 14
+
+	ch := make(chan prometheus.Metric, 10)
+	if err := e.track(collectorUserDetails, func() error { return e.exportUserDetails(context.Background(), ch) }); err != nil {
+		t.Fatalf("exportUserDetails() error = %v", err)
+	}
+	close(ch)
+	admins := -1.0
+	for m := range ch {
+		if m.Desc() != userDetailsMetrics["admins"] {
+			continue
+		}
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		admins = metric.GetGauge().GetValue()
+	}
+	if admins != 1 {
+		t.Errorf("admins = %v, want 1", admins)
+	}
+	if skipped := e.Status().Collectors[collectorUserDetails].LastSkipped; skipped != 1 {
+		t.Errorf("LastSkipped = %d, want 1", skipped)
+	}
+}
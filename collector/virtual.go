@@ -0,0 +1,70 @@
+package collector
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// memberStatusMissing marks a virtual repository member which does not exist anymore.
+const memberStatusMissing = "missing"
+
+func (e *Exporter) exportVirtualRepositories(ch chan<- prometheus.Metric) error {
+	snapshot, err := e.lastRepoConfigs()
+	if err != nil {
+		return err
+	}
+
+	// map[<repo key>] <rclass>
+	rclasses := make(map[string]string, len(snapshot.repositories))
+	for _, repo := range snapshot.repositories {
+		rclasses[repo.Key] = strings.ToLower(repo.Type)
+	}
+	for _, repo := range snapshot.repositories {
+		repoConfig, exists := snapshot.configs[repo.Key]
+		if !exists || rclasses[repo.Key] != "virtual" {
+			continue
+		}
+		packageType := strings.ToLower(repoConfig.PackageType)
+		members := float64(len(repoConfig.Repositories))
+		e.logger.Debug(
+			logDbgMsgRegMetric,
+			"metric", "virtualRepoMembers",
+			"repo", repoConfig.Key,
+			"package_type", packageType,
+			"default_deployment_repo", repoConfig.DefaultDeploymentRepo,
+			"value", members,
+		)
+		ch <- prometheus.MustNewConstMetric(virtualRepoMetrics["members"], prometheus.GaugeValue, members, repoConfig.Key, packageType, repoConfig.NodeId)
+		ch <- prometheus.MustNewConstMetric(virtualRepoMetrics["defaultDeploymentRepo"], prometheus.GaugeValue, 1, repoConfig.Key, packageType, repoConfig.DefaultDeploymentRepo, repoConfig.NodeId)
+
+		for _, member := range repoConfig.Repositories {
+			memberRclass, exists := rclasses[member]
+			var memberStatus string
+			switch {
+			case !exists || snapshot.missing[member]:
+				memberStatus = memberStatusMissing
+			case memberRclass == "virtual":
+				// Virtual members have no status of their own
+				memberStatus = remoteStatusOnline
+			default:
+				memberConfig, exists := snapshot.configs[member]
+				if !exists {
+					// The configuration of the member couldn't be fetched
+					continue
+				}
+				memberStatus = remoteRepoStatus(memberConfig)
+			}
+			e.logger.Debug(
+				logDbgMsgRegMetric,
+				"metric", "virtualRepoMember",
+				"repo", repoConfig.Key,
+				"member", member,
+				"member_rclass", memberRclass,
+				"member_status", memberStatus,
+			)
+			ch <- prometheus.MustNewConstMetric(virtualRepoMetrics["member"], prometheus.GaugeValue, 1, repoConfig.Key, packageType, member, memberRclass, memberStatus, repoConfig.NodeId)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestExportVirtualRepositoriesMemberStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/repositories":
+			w.Write([]byte(`[
+				{"key":"libs","type":"VIRTUAL","packageType":"Maven"},
+				{"key":"libs-release","type":"LOCAL","packageType":"Maven"},
+				{"key":"libs-federated","type":"FEDERATED","packageType":"Maven"},
+				{"key":"libs-deleted","type":"LOCAL","packageType":"Maven"},
+				{"key":"maven-central","type":"REMOTE","packageType":"Maven"}
+			]`))
+		case "/api/repositories/libs":
+			w.Write([]byte(`{"key":"libs","rclass":"virtual","packageType":"maven","defaultDeploymentRepo":"libs-release",
+				"repositories":["libs-release","libs-federated","libs-deleted","libs-unknown","maven-central"]}`))
+		case "/api/repositories/libs-release":
+			w.Write([]byte(`{"key":"libs-release","rclass":"local","packageType":"maven"}`))
+		case "/api/repositories/libs-federated":
+			w.Write([]byte(`{"key":"libs-federated","rclass":"federated","packageType":"maven","blackedOut":true}`))
+		case "/api/repositories/maven-central":
+			w.Write([]byte(`{"key":"maven-central","rclass":"remote","packageType":"maven","offline":true}`))
+		default:
+			// libs-deleted was deleted since it was listed
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[{"status":404,"message":"Not Found"}]}`))
+		}
+	}))
+	defer server.Close()
+	e := newTestExporter(t, server.URL)
+	e.exporterRuntimeConfig.OptionalMetrics.VirtualRepositories = true
+
+	if err := e.fetchRepoConfigs(context.Background(), nil); err != nil {
+		t.Fatalf("fetchRepoConfigs() error = %v", err)
+	}
+	ch := make(chan prometheus.Metric, 10)
+	err := e.exportVirtualRepositories(ch)
+	if err != nil {
+		t.Fatalf("exportVirtualRepositories() error = %v", err)
+	}
+	close(ch)
+	statuses := map[string]string{}
+	for m := range ch {
+		if m.Desc() != virtualRepoMetrics["member"] {
+			continue
+		}
+		var metric dto.Metric
+		if err := m.Write(&metric); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		statuses[labelValue(&metric, "member")] = labelValue(&metric, "member_status")
+	}
+	want := map[string]string{
+		"libs-release":   remoteStatusOnline,
+		"libs-federated": remoteStatusBlackedOut,
+		"libs-deleted":   memberStatusMissing,
+		"libs-unknown":   memberStatusMissing,
+		"maven-central":  remoteStatusOffline,
+	}
+	if len(statuses) != len(want) {
+		t.Errorf("Expected %d members, got %v", len(want), statuses)
+	}
+	for member, status := range want {
+		if statuses[member] != status {
+			t.Errorf("Status of member %s = %q, want %q", member, statuses[member], status)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Value modes of user-defined AQL queries.
+const (
+	AQLValueCount = "count"
+	AQLValueSum   = "sum"
+)
+
+// defaultAQLQueryInterval is used for queries without an interval.
+const defaultAQLQueryInterval = 5 * time.Minute
+
+var (
+	metricNameRe    = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	invalidLabelRe  = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	reservedLabelRe = regexp.MustCompile(`^(__.*|node_id)$`)
+)
+
+// AQLQuery is a user-defined AQL query exported as a gauge named artifactory_aql_<name>.
+type AQLQuery struct {
+	Name     string        `yaml:"name"`
+	Help     string        `yaml:"help"`
+	Query    string        `yaml:"query"`
+	Value    string        `yaml:"value"`
+	Field    string        `yaml:"field"`
+	Labels   []string      `yaml:"labels"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+// AQLLabel is a label of a user-defined AQL query, taken either from a field
+// of each result (e.g. "repo") or from the value of a property ("@<key>").
+type AQLLabel struct {
+	Name     string
+	Field    string
+	Property string
+}
+
+type aqlQueriesConfig struct {
+	Queries []AQLQuery `yaml:"queries"`
+}
+
+// LabelSpecs returns the labels of the query in order.
+func (q AQLQuery) LabelSpecs() []AQLLabel {
+	specs := make([]AQLLabel, 0, len(q.Labels))
+	for _, label := range q.Labels {
+		if len(label) > 1 && label[0] == '@' {
+			key := label[1:]
+			specs = append(specs, AQLLabel{Name: invalidLabelRe.ReplaceAllString(key, "_"), Property: key})
+			continue
+		}
+		specs = append(specs, AQLLabel{Name: invalidLabelRe.ReplaceAllString(label, "_"), Field: label})
+	}
+	return specs
+}
+
+// loadAQLQueries reads and validates the user-defined AQL queries from path.
+func loadAQLQueries(path string) ([]AQLQuery, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading AQL queries file: %w", err)
+	}
+	var file aqlQueriesConfig
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing AQL queries file %s: %w", path, err)
+	}
+	names := map[string]bool{}
+	for i := range file.Queries {
+		q := &file.Queries[i]
+		if err := q.validate(); err != nil {
+			return nil, fmt.Errorf("AQL query %d (%s): %w", i, q.Name, err)
+		}
+		if names[q.Name] {
+			return nil, fmt.Errorf("AQL query %d: duplicate name %s", i, q.Name)
+		}
+		names[q.Name] = true
+	}
+	return file.Queries, nil
+}
+
+// validate checks the query and fills in defaults.
+func (q *AQLQuery) validate() error {
+	if !metricNameRe.MatchString(q.Name) {
+		return fmt.Errorf("name must match %s", metricNameRe)
+	}
+	if q.Query == "" {
+		return fmt.Errorf("query must be set")
+	}
+	if q.Help == "" {
+		q.Help = fmt.Sprintf("User-defined AQL query %s.", q.Name)
+	}
+	switch q.Value {
+	case "":
+		q.Value = AQLValueCount
+	case AQLValueCount:
+	case AQLValueSum:
+		if q.Field == "" {
+			return fmt.Errorf("field must be set with value %s", AQLValueSum)
+		}
+	default:
+		return fmt.Errorf("unknown value %q, must be %s or %s", q.Value, AQLValueCount, AQLValueSum)
+	}
+	if q.Interval == 0 {
+		q.Interval = defaultAQLQueryInterval
+	} else if q.Interval < 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	labels := map[string]bool{}
+	for _, spec := range q.LabelSpecs() {
+		if spec.Name == "" || spec.Name[0] >= '0' && spec.Name[0] <= '9' || reservedLabelRe.MatchString(spec.Name) {
+			return fmt.Errorf("invalid label %q", spec.Name)
+		}
+		if labels[spec.Name] {
+			return fmt.Errorf("duplicate label %s", spec.Name)
+		}
+		labels[spec.Name] = true
+	}
+	return nil
+}
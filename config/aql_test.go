@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadAQLQueries(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name: "Valid queries",
+			content: `queries:
+  - name: docker_manifests
+    query: 'items.find({"name":"manifest.json"}).include("repo")'
+    labels: [repo]
+  - name: release_bytes
+    help: Size of release artifacts.
+    query: 'items.find({"@release":"true"}).include("repo","size","property")'
+    value: sum
+    field: size
+    labels: [repo, "@build.name"]
+    interval: 1h
+`,
+		},
+		{"Missing query", "queries:\n  - name: missing_query\n", true},
+		{"Invalid name", "queries:\n  - name: bad-name\n    query: items.find()\n", true},
+		{"Sum without field", "queries:\n  - name: q\n    query: items.find()\n    value: sum\n", true},
+		{"Unknown value", "queries:\n  - name: q\n    query: items.find()\n    value: avg\n", true},
+		{"Reserved label", "queries:\n  - name: q\n    query: items.find()\n    labels: [node_id]\n", true},
+		{"Duplicate name", "queries:\n  - name: q\n    query: items.find()\n  - name: q\n    query: items.find()\n", true},
+		{"Unknown key", "queries:\n  - name: q\n    query: items.find()\n    lables: [repo]\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "aql.yml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			queries, err := loadAQLQueries(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadAQLQueries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if queries[0].Value != AQLValueCount || queries[0].Interval != defaultAQLQueryInterval {
+				t.Errorf("Expected defaults to be applied, got %+v", queries[0])
+			}
+			if queries[1].Interval != time.Hour {
+				t.Errorf("Interval = %v, want 1h", queries[1].Interval)
+			}
+			specs := queries[1].LabelSpecs()
+			if specs[1].Name != "build_name" || specs[1].Property != "build.name" {
+				t.Errorf("Unexpected property label %+v", specs[1])
+			}
+		})
+	}
+}
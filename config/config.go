@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"regexp"
 	"time"
 
+	"github.com/alecthomas/units"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/prometheus/common/version"
+	"github.com/prometheus/exporter-toolkit/web"
 	"gopkg.in/alecthomas/kingpin.v2"
 
 	l "github.com/peimanja/artifactory_exporter/logger"
@@ -18,18 +21,51 @@ var (
 	flagLogLevel           = kingpin.Flag(l.LevelFlagName, l.LevelFlagHelp).Default(l.LevelDefault).Enum(l.LevelsAvailable...)
 	listenAddress          = kingpin.Flag("web.listen-address", "Address to listen on for web interface and telemetry.").Envar("WEB_LISTEN_ADDR").Default(":9531").String()
 	metricsPath            = kingpin.Flag("web.telemetry-path", "Path under which to expose metrics.").Envar("WEB_TELEMETRY_PATH").Default("/metrics").String()
+	webConfigFile          = kingpin.Flag("web.config.file", "Path to configuration file that can enable TLS or authentication. See: https://github.com/prometheus/exporter-toolkit/blob/master/docs/web-configuration.md").Envar("WEB_CONFIG_FILE").Default("").String()
+	readyCheckInterval     = kingpin.Flag("web.ready-check-interval", "Time the result of the last Artifactory health and authentication check (or scrape) is served on /-/ready before Artifactory is checked again.").Envar("WEB_READY_CHECK_INTERVAL").Default("1m").Duration()
+	debugLastScrape        = kingpin.Flag("web.debug-last-scrape", "Keep redacted copies of the last Artifactory API responses and serve them on /debug/last-scrape.").Envar("WEB_DEBUG_LAST_SCRAPE").Default("false").Bool()
+	shutdownTimeout        = kingpin.Flag("web.shutdown-timeout", "Time to wait for in-flight scrapes to finish on shutdown.").Envar("WEB_SHUTDOWN_TIMEOUT").Default("30s").Duration()
 	artiScrapeURI          = kingpin.Flag("artifactory.scrape-uri", "URI on which to scrape JFrog Artifactory.").Envar("ARTI_SCRAPE_URI").Default("http://localhost:8081/artifactory").String()
 	artiSSLVerify          = kingpin.Flag("artifactory.ssl-verify", "Flag that enables SSL certificate verification for the scrape URI").Envar("ARTI_SSL_VERIFY").Default("false").Bool()
 	artiTimeout            = kingpin.Flag("artifactory.timeout", "Timeout for trying to get stats from JFrog Artifactory.").Envar("ARTI_TIMEOUT").Default("5s").Duration()
+	artiAQLTimeout         = kingpin.Flag("artifactory.aql-timeout", "Timeout for an AQL query whose results are read while they are streamed, including the time to read them. Applies to each page of paged queries").Envar("ARTI_AQL_TIMEOUT").Default("5m").Duration()
 	optionalMetrics        = kingpin.Flag("optional-metric", fmt.Sprintf("optional metric to be enabled. Valid metrics are: %v", optionalMetricsList)).PlaceHolder("metric-name").Strings()
 	accessFederationTarget = kingpin.Flag("access-federation-target", "URL of Jfrog Access Federation Target server. Only required if optional metric AccessFederationValidate is enabled").Envar("ACCESS_FEDERATION_TARGET").String()
 	useCache               = kingpin.Flag("use-cache", "Use cache for API responses to circumvent timeouts").Envar("USE_CACHE").Default("false").Bool()
 	cacheTimeout           = kingpin.Flag("cache-timeout", "Timeout for API responses to fallback to cache").Envar("CACHE_TIMEOUT").Default("30s").Duration()
 	cacheTTL               = kingpin.Flag("cache-ttl", "Time to live for cached API responses").Envar("CACHE_TTL").Default("5m").Duration()
+	repoConfigInterval     = kingpin.Flag("repository-config-interval", "Interval at which the configuration of every repository is fetched. Only used if optional metric repositories, remote_repositories or virtual_repositories is enabled").Envar("REPOSITORY_CONFIG_INTERVAL").Default("5m").Duration()
+	remoteRepoURLCheck     = kingpin.Flag("remote-repo-url-check", "Probe the upstream URL of each remote repository. Only used if optional metric remote_repositories is enabled").Envar("REMOTE_REPO_URL_CHECK").Default("false").Bool()
+	remoteRepoURLTimeout   = kingpin.Flag("remote-repo-url-check-timeout", "Timeout for probing the upstream URL of a remote repository").Envar("REMOTE_REPO_URL_CHECK_TIMEOUT").Default("5s").Duration()
+	remoteRepoURLInterval  = kingpin.Flag("remote-repo-url-check-interval", "Interval at which the upstream URLs of remote repositories are probed").Envar("REMOTE_REPO_URL_CHECK_INTERVAL").Default("5m").Duration()
+	remoteRepoURLSSLVerify = kingpin.Flag("remote-repo-url-check-ssl-verify", "Verify the TLS certificates of the upstream URLs of remote repositories, independently of --artifactory.ssl-verify").Envar("REMOTE_REPO_URL_CHECK_SSL_VERIFY").Default("true").Bool()
+	storageInfoRefresh     = kingpin.Flag("storage-info-refresh-interval", "Interval at which to request a recalculation of the Artifactory storage summary (POST /api/storageinfo/calculate). 0 disables it").Envar("STORAGE_INFO_REFRESH_INTERVAL").Default("0s").Duration()
+	storageGrowthWindow    = kingpin.Flag("storage-growth-window", "Time window of the in-memory storage history used to compute growth rates and the file store full forecast. 0 disables it").Envar("STORAGE_GROWTH_WINDOW").Default("0s").Duration()
+	staleArtifactsDays     = kingpin.Flag("stale-artifacts-days", "Number of days without download after which an artifact is stale. Pass multiple times for multiple thresholds. Only used if optional metric stale_artifacts is enabled").Envar("STALE_ARTIFACTS_DAYS").Default("90").Ints()
+	staleArtifactsInterval = kingpin.Flag("stale-artifacts-interval", "Interval at which stale artifacts are searched. Only used if optional metric stale_artifacts is enabled").Envar("STALE_ARTIFACTS_INTERVAL").Default("6h").Duration()
+	topArtifactsCount      = kingpin.Flag("top-artifacts-count", fmt.Sprintf("Number of largest and most downloaded artifacts reported, globally or per repository (at most %d). Only used if optional metric top_artifacts is enabled", MaxTopArtifactsCount)).Envar("TOP_ARTIFACTS_COUNT").Default("10").Int()
+	topArtifactsPerRepo    = kingpin.Flag("top-artifacts-per-repo", "Report the top artifacts of each repository instead of the whole instance. Only used if optional metric top_artifacts is enabled").Envar("TOP_ARTIFACTS_PER_REPO").Default("false").Bool()
+	topArtifactsInterval   = kingpin.Flag("top-artifacts-interval", "Interval at which the top artifacts are searched. Only used if optional metric top_artifacts is enabled").Envar("TOP_ARTIFACTS_INTERVAL").Default("1h").Duration()
+	artifactSizeBuckets    = kingpin.Flag("artifact-size-buckets", "Upper bounds of the artifact size histogram buckets (e.g. 1MB). Pass multiple times for multiple buckets. Only used if optional metric artifact_size is enabled").Envar("ARTIFACT_SIZE_BUCKETS").Default("1KB", "100KB", "1MB", "10MB", "100MB", "1GB", "10GB").Strings()
+	artifactSizeInterval   = kingpin.Flag("artifact-size-interval", "Interval at which the artifact size histograms are computed. Only used if optional metric artifact_size is enabled").Envar("ARTIFACT_SIZE_INTERVAL").Default("6h").Duration()
+	dockerInterval         = kingpin.Flag("docker-interval", "Interval at which Docker images and tags are counted. Only used if optional metric docker is enabled").Envar("DOCKER_INTERVAL").Default("1h").Duration()
+	buildsInterval         = kingpin.Flag("builds-interval", "Interval at which builds are counted. Only used if optional metric builds is enabled").Envar("BUILDS_INTERVAL").Default("15m").Duration()
+	buildNameFilter        = kingpin.Flag("build-name-filter", "Regular expression matching the build names to report, all build names by default. Only used if optional metric builds is enabled").Envar("BUILD_NAME_FILTER").Default("").String()
+	permTargetsInterval    = kingpin.Flag("permission-targets-interval", "Interval at which the details of every permission target are fetched. Only used if optional metric permission_targets is enabled").Envar("PERMISSION_TARGETS_INTERVAL").Default("1h").Duration()
+	userInactiveDays       = kingpin.Flag("user-inactive-days", "Number of days without login after which a user is inactive. Pass multiple times for multiple thresholds. Only used if optional metric user_details is enabled").Envar("USER_INACTIVE_DAYS").Default("30", "90").Ints()
+	userPasswordExpiryDays = kingpin.Flag("user-password-expiry-days", "Number of days within which a password expiring is reported. Only used if optional metric user_details is enabled").Envar("USER_PASSWORD_EXPIRY_DAYS").Default("14").Int()
+	userDetailsInterval    = kingpin.Flag("user-details-interval", "Interval at which the details of every user are fetched. Only used if optional metric user_details is enabled").Envar("USER_DETAILS_INTERVAL").Default("1h").Duration()
+	accessTokenExpiryDays  = kingpin.Flag("access-token-expiry-days", "Number of days within which an access token expiring is reported. Pass multiple times for multiple windows. Only used if optional metric access_tokens is enabled").Envar("ACCESS_TOKEN_EXPIRY_DAYS").Default("7", "30").Ints()
+	groupDetailsInterval   = kingpin.Flag("group-details-interval", "Interval at which the details of every group are fetched. Only used if optional metric group_details is enabled").Envar("GROUP_DETAILS_INTERVAL").Default("1h").Duration()
+	aqlQueriesFile         = kingpin.Flag("aql-queries-file", "Path to a YAML file with user-defined AQL queries exported as metrics.").Envar("AQL_QUERIES_FILE").Default("").String()
 	artifactsTimeIntervals = kingpin.Flag("artifacts-time-interval", "Time interval for created and downloaded stats").Default("1m", "5m", "15m").DurationList()
+	artifactsAQLPageSize   = kingpin.Flag("artifacts-aql-page-size", "Maximum number of items returned by a single paged AQL query of the artifacts, stale_artifacts, artifact_size and docker optional metrics, larger results are paged").Envar("ARTIFACTS_AQL_PAGE_SIZE").Default("10000").Int()
 )
 
-var optionalMetricsList = []string{"artifacts", "replication_status", "federation_status", "open_metrics", "access_federation_validate", "background_tasks"}
+// MaxTopArtifactsCount bounds the number of top artifacts reported per list.
+const MaxTopArtifactsCount = 100
+
+var optionalMetricsList = []string{"artifacts", "replication_status", "federation_status", "open_metrics", "access_federation_validate", "background_tasks", "repositories", "remote_repositories", "virtual_repositories", "projects", "stale_artifacts", "top_artifacts", "artifact_size", "docker", "builds", "permission_targets", "user_details", "access_tokens", "group_details", "gpg_keys"}
 
 // Credentials represents Username and Password or API Key for
 // Artifactory Authentication
@@ -48,9 +84,24 @@ type OptionalMetrics struct {
 	OpenMetrics              bool `yaml:"open_metrics"`
 	AccessFederationValidate bool `yaml:"access_federation_validate"`
 	BackgroundTasks          bool `yaml:"background_tasks"`
+	Repositories             bool `yaml:"repositories"`
+	RemoteRepositories       bool `yaml:"remote_repositories"`
+	VirtualRepositories      bool `yaml:"virtual_repositories"`
+	Projects                 bool `yaml:"projects"`
+	StaleArtifacts           bool `yaml:"stale_artifacts"`
+	TopArtifacts             bool `yaml:"top_artifacts"`
+	ArtifactSize             bool `yaml:"artifact_size"`
+	Docker                   bool `yaml:"docker"`
+	Builds                   bool `yaml:"builds"`
+	PermissionTargets        bool `yaml:"permission_targets"`
+	UserDetails              bool `yaml:"user_details"`
+	AccessTokens             bool `yaml:"access_tokens"`
+	GroupDetails             bool `yaml:"group_details"`
+	GPGKeys                  bool `yaml:"gpg_keys"`
 }
 
 type timeInterval struct {
+	Window      time.Duration
 	Duration    int
 	Unit        string
 	Period      string
@@ -58,24 +109,54 @@ type timeInterval struct {
 }
 
 type ExporterRuntimeConfig struct {
-	OptionalMetrics        OptionalMetrics
-	ArtifactsTimeIntervals []timeInterval
+	OptionalMetrics            OptionalMetrics
+	ArtifactsTimeIntervals     []timeInterval
+	RepoConfigInterval         time.Duration
+	RemoteRepoURLCheck         bool
+	RemoteRepoURLCheckInterval time.Duration
+	StorageInfoRefreshInterval time.Duration
+	StorageGrowthWindow        time.Duration
+	AQLQueries                 []AQLQuery
+	ArtifactsAQLPageSize       int
+	StaleArtifactsDays         []int
+	StaleArtifactsInterval     time.Duration
+	TopArtifactsCount          int
+	TopArtifactsPerRepo        bool
+	TopArtifactsInterval       time.Duration
+	ArtifactSizeBuckets        []float64
+	ArtifactSizeInterval       time.Duration
+	DockerInterval             time.Duration
+	BuildsInterval             time.Duration
+	BuildNameFilter            *regexp.Regexp
+	PermissionTargetsInterval  time.Duration
+	UserInactiveDays           []int
+	UserPasswordExpiryDays     int
+	UserDetailsInterval        time.Duration
+	AccessTokenExpiryDays      []int
+	GroupDetailsInterval       time.Duration
 }
 
 // Config represents all configuration options for running the Exporter.
 type Config struct {
-	ListenAddress          string
-	MetricsPath            string
-	ArtiScrapeURI          string
-	Credentials            *Credentials
-	ArtiSSLVerify          bool
-	ArtiTimeout            time.Duration
-	UseCache               bool
-	CacheTimeout           time.Duration
-	CacheTTL               time.Duration
-	ExporterRuntimeConfig  *ExporterRuntimeConfig
-	AccessFederationTarget string
-	Logger                 *slog.Logger
+	ListenAddress             string
+	MetricsPath               string
+	WebConfigFile             string
+	ShutdownTimeout           time.Duration
+	ReadyCheckInterval        time.Duration
+	DebugLastScrape           bool
+	ArtiScrapeURI             string
+	Credentials               *Credentials
+	ArtiSSLVerify             bool
+	ArtiTimeout               time.Duration
+	ArtiAQLTimeout            time.Duration
+	UseCache                  bool
+	CacheTimeout              time.Duration
+	CacheTTL                  time.Duration
+	RemoteRepoURLCheckTimeout time.Duration
+	RemoteRepoURLSSLVerify    bool
+	ExporterRuntimeConfig     *ExporterRuntimeConfig
+	AccessFederationTarget    string
+	Logger                    *slog.Logger
 }
 
 func getAqlTimeFormat(d time.Duration) (int, string) {
@@ -92,6 +173,24 @@ func getAqlTimeFormat(d time.Duration) (int, string) {
 	}
 }
 
+// parseSizeBuckets parses histogram bucket upper bounds given as sizes
+// (e.g. 10MB, with 1KB = 1024 bytes) into a strictly increasing list of bytes.
+func parseSizeBuckets(sizes []string) ([]float64, error) {
+	buckets := make([]float64, 0, len(sizes))
+	for _, size := range sizes {
+		bytes, err := units.ParseBase2Bytes(size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bucket size %q: %w", size, err)
+		}
+		bucket := float64(bytes)
+		if bucket <= 0 || len(buckets) > 0 && bucket <= buckets[len(buckets)-1] {
+			return nil, fmt.Errorf("bucket sizes must be positive and increasing, got %v", sizes)
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
+
 // NewConfig Creates Config for Artifactory exporter
 func NewConfig() (*Config, error) {
 
@@ -117,6 +216,10 @@ func NewConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if err := web.Validate(*webConfigFile); err != nil {
+		return nil, fmt.Errorf("invalid web configuration file %s: %w", *webConfigFile, err)
+	}
+
 	optMetrics := OptionalMetrics{}
 	for _, metric := range *optionalMetrics {
 		switch metric {
@@ -132,6 +235,34 @@ func NewConfig() (*Config, error) {
 			optMetrics.AccessFederationValidate = true
 		case "background_tasks":
 			optMetrics.BackgroundTasks = true
+		case "repositories":
+			optMetrics.Repositories = true
+		case "remote_repositories":
+			optMetrics.RemoteRepositories = true
+		case "virtual_repositories":
+			optMetrics.VirtualRepositories = true
+		case "projects":
+			optMetrics.Projects = true
+		case "stale_artifacts":
+			optMetrics.StaleArtifacts = true
+		case "top_artifacts":
+			optMetrics.TopArtifacts = true
+		case "artifact_size":
+			optMetrics.ArtifactSize = true
+		case "docker":
+			optMetrics.Docker = true
+		case "builds":
+			optMetrics.Builds = true
+		case "permission_targets":
+			optMetrics.PermissionTargets = true
+		case "user_details":
+			optMetrics.UserDetails = true
+		case "access_tokens":
+			optMetrics.AccessTokens = true
+		case "group_details":
+			optMetrics.GroupDetails = true
+		case "gpg_keys":
+			optMetrics.GPGKeys = true
 		default:
 			return nil, fmt.Errorf("unknown optional metric: %s. Valid optional metrics are: %v", metric, optionalMetricsList)
 		}
@@ -141,6 +272,7 @@ func NewConfig() (*Config, error) {
 	for idx, interval := range *artifactsTimeIntervals {
 		duration, unit := getAqlTimeFormat(interval)
 		timeIntervals[idx] = timeInterval{
+			Window:      interval,
 			Duration:    duration,
 			Unit:        unit,
 			Period:      fmt.Sprintf("%d%s", duration, unit),
@@ -149,8 +281,114 @@ func NewConfig() (*Config, error) {
 	}
 
 	exporterRuntimeConfig := ExporterRuntimeConfig{
-		OptionalMetrics:        optMetrics,
-		ArtifactsTimeIntervals: timeIntervals,
+		OptionalMetrics:            optMetrics,
+		ArtifactsTimeIntervals:     timeIntervals,
+		RepoConfigInterval:         *repoConfigInterval,
+		RemoteRepoURLCheck:         *remoteRepoURLCheck,
+		RemoteRepoURLCheckInterval: *remoteRepoURLInterval,
+		StorageInfoRefreshInterval: *storageInfoRefresh,
+		StorageGrowthWindow:        *storageGrowthWindow,
+		ArtifactsAQLPageSize:       *artifactsAQLPageSize,
+		StaleArtifactsDays:         *staleArtifactsDays,
+		StaleArtifactsInterval:     *staleArtifactsInterval,
+		TopArtifactsCount:          *topArtifactsCount,
+		TopArtifactsPerRepo:        *topArtifactsPerRepo,
+		TopArtifactsInterval:       *topArtifactsInterval,
+		ArtifactSizeInterval:       *artifactSizeInterval,
+		DockerInterval:             *dockerInterval,
+		BuildsInterval:             *buildsInterval,
+		PermissionTargetsInterval:  *permTargetsInterval,
+		UserInactiveDays:           *userInactiveDays,
+		UserPasswordExpiryDays:     *userPasswordExpiryDays,
+		UserDetailsInterval:        *userDetailsInterval,
+		AccessTokenExpiryDays:      *accessTokenExpiryDays,
+		GroupDetailsInterval:       *groupDetailsInterval,
+	}
+	if *artiAQLTimeout <= 0 {
+		return nil, fmt.Errorf("AQL timeout must be positive, got %s", *artiAQLTimeout)
+	}
+	if exporterRuntimeConfig.ArtifactsAQLPageSize <= 0 {
+		return nil, fmt.Errorf("artifacts AQL page size must be positive, got %d", exporterRuntimeConfig.ArtifactsAQLPageSize)
+	}
+	if (optMetrics.Repositories || optMetrics.RemoteRepositories || optMetrics.VirtualRepositories) && exporterRuntimeConfig.RepoConfigInterval <= 0 {
+		return nil, fmt.Errorf("repository config interval must be positive, got %s", exporterRuntimeConfig.RepoConfigInterval)
+	}
+	if optMetrics.RemoteRepositories && exporterRuntimeConfig.RemoteRepoURLCheck && exporterRuntimeConfig.RemoteRepoURLCheckInterval <= 0 {
+		return nil, fmt.Errorf("remote repository URL check interval must be positive, got %s", exporterRuntimeConfig.RemoteRepoURLCheckInterval)
+	}
+	if optMetrics.StaleArtifacts {
+		for _, days := range exporterRuntimeConfig.StaleArtifactsDays {
+			if days <= 0 {
+				return nil, fmt.Errorf("stale artifacts days must be positive, got %d", days)
+			}
+		}
+		if exporterRuntimeConfig.StaleArtifactsInterval <= 0 {
+			return nil, fmt.Errorf("stale artifacts interval must be positive, got %s", exporterRuntimeConfig.StaleArtifactsInterval)
+		}
+	}
+	if optMetrics.TopArtifacts {
+		if count := exporterRuntimeConfig.TopArtifactsCount; count <= 0 || count > MaxTopArtifactsCount {
+			return nil, fmt.Errorf("top artifacts count must be between 1 and %d, got %d", MaxTopArtifactsCount, count)
+		}
+		if exporterRuntimeConfig.TopArtifactsInterval <= 0 {
+			return nil, fmt.Errorf("top artifacts interval must be positive, got %s", exporterRuntimeConfig.TopArtifactsInterval)
+		}
+	}
+	if optMetrics.ArtifactSize {
+		exporterRuntimeConfig.ArtifactSizeBuckets, err = parseSizeBuckets(*artifactSizeBuckets)
+		if err != nil {
+			return nil, err
+		}
+		if exporterRuntimeConfig.ArtifactSizeInterval <= 0 {
+			return nil, fmt.Errorf("artifact size interval must be positive, got %s", exporterRuntimeConfig.ArtifactSizeInterval)
+		}
+	}
+	if optMetrics.Docker && exporterRuntimeConfig.DockerInterval <= 0 {
+		return nil, fmt.Errorf("docker interval must be positive, got %s", exporterRuntimeConfig.DockerInterval)
+	}
+	if optMetrics.Builds {
+		if exporterRuntimeConfig.BuildsInterval <= 0 {
+			return nil, fmt.Errorf("builds interval must be positive, got %s", exporterRuntimeConfig.BuildsInterval)
+		}
+		if *buildNameFilter != "" {
+			exporterRuntimeConfig.BuildNameFilter, err = regexp.Compile(*buildNameFilter)
+			if err != nil {
+				return nil, fmt.Errorf("invalid build name filter %q: %w", *buildNameFilter, err)
+			}
+		}
+	}
+	if optMetrics.PermissionTargets && exporterRuntimeConfig.PermissionTargetsInterval <= 0 {
+		return nil, fmt.Errorf("permission targets interval must be positive, got %s", exporterRuntimeConfig.PermissionTargetsInterval)
+	}
+	if optMetrics.UserDetails {
+		for _, days := range exporterRuntimeConfig.UserInactiveDays {
+			if days <= 0 {
+				return nil, fmt.Errorf("user inactive days must be positive, got %d", days)
+			}
+		}
+		if exporterRuntimeConfig.UserPasswordExpiryDays <= 0 {
+			return nil, fmt.Errorf("user password expiry days must be positive, got %d", exporterRuntimeConfig.UserPasswordExpiryDays)
+		}
+		if exporterRuntimeConfig.UserDetailsInterval <= 0 {
+			return nil, fmt.Errorf("user details interval must be positive, got %s", exporterRuntimeConfig.UserDetailsInterval)
+		}
+	}
+	if optMetrics.AccessTokens {
+		for _, days := range exporterRuntimeConfig.AccessTokenExpiryDays {
+			if days <= 0 {
+				return nil, fmt.Errorf("access token expiry days must be positive, got %d", days)
+			}
+		}
+	}
+	if optMetrics.GroupDetails && exporterRuntimeConfig.GroupDetailsInterval <= 0 {
+		return nil, fmt.Errorf("group details interval must be positive, got %s", exporterRuntimeConfig.GroupDetailsInterval)
+	}
+
+	if *aqlQueriesFile != "" {
+		exporterRuntimeConfig.AQLQueries, err = loadAQLQueries(*aqlQueriesFile)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if *accessFederationTarget != "" {
@@ -169,18 +407,25 @@ func NewConfig() (*Config, error) {
 		},
 	)
 	return &Config{
-		ListenAddress:          *listenAddress,
-		MetricsPath:            *metricsPath,
-		ArtiScrapeURI:          *artiScrapeURI,
-		Credentials:            &credentials,
-		ArtiSSLVerify:          *artiSSLVerify,
-		ArtiTimeout:            *artiTimeout,
-		UseCache:               *useCache,
-		CacheTimeout:           *cacheTimeout,
-		CacheTTL:               *cacheTTL,
-		ExporterRuntimeConfig:  &exporterRuntimeConfig,
-		AccessFederationTarget: *accessFederationTarget,
-		Logger:                 logger,
+		ListenAddress:             *listenAddress,
+		MetricsPath:               *metricsPath,
+		WebConfigFile:             *webConfigFile,
+		ShutdownTimeout:           *shutdownTimeout,
+		ReadyCheckInterval:        *readyCheckInterval,
+		DebugLastScrape:           *debugLastScrape,
+		ArtiScrapeURI:             *artiScrapeURI,
+		Credentials:               &credentials,
+		ArtiSSLVerify:             *artiSSLVerify,
+		ArtiTimeout:               *artiTimeout,
+		ArtiAQLTimeout:            *artiAQLTimeout,
+		UseCache:                  *useCache,
+		CacheTimeout:              *cacheTimeout,
+		CacheTTL:                  *cacheTTL,
+		RemoteRepoURLCheckTimeout: *remoteRepoURLTimeout,
+		RemoteRepoURLSSLVerify:    *remoteRepoURLSSLVerify,
+		ExporterRuntimeConfig:     &exporterRuntimeConfig,
+		AccessFederationTarget:    *accessFederationTarget,
+		Logger:                    logger,
 	}, nil
 
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -369,6 +370,20 @@ func TestOptionalMetricsList(t *testing.T) {
 		"open_metrics",
 		"access_federation_validate",
 		"background_tasks",
+		"repositories",
+		"remote_repositories",
+		"virtual_repositories",
+		"projects",
+		"stale_artifacts",
+		"top_artifacts",
+		"artifact_size",
+		"docker",
+		"builds",
+		"permission_targets",
+		"user_details",
+		"access_tokens",
+		"group_details",
+		"gpg_keys",
 	}
 
 	if len(optionalMetricsList) != len(expectedMetrics) {
@@ -597,3 +612,27 @@ func TestConfigurationEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestParseSizeBuckets(t *testing.T) {
+	tests := []struct {
+		name    string
+		sizes   []string
+		want    []float64
+		wantErr bool
+	}{
+		{"Units", []string{"512B", "1KB", "10MiB"}, []float64{512, 1024, 10 << 20}, false},
+		{"Not increasing", []string{"1MB", "1KB"}, nil, true},
+		{"Invalid", []string{"big"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSizeBuckets(tt.sizes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSizeBuckets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) && !tt.wantErr {
+				t.Errorf("parseSizeBuckets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
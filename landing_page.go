@@ -0,0 +1,133 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+
+	"github.com/prometheus/common/version"
+
+	"github.com/peimanja/artifactory_exporter/artifactory"
+	"github.com/peimanja/artifactory_exporter/collector"
+	"github.com/peimanja/artifactory_exporter/config"
+)
+
+var landingPageTemplate = template.Must(template.New("landing").Parse(`<html>
+<head><title>JFrog Artifactory Exporter</title></head>
+<body>
+<h1>JFrog Artifactory Exporter</h1>
+<p>Version {{.Version}} (revision {{.Revision}}, branch {{.Branch}}, built {{.BuildDate}})</p>
+<ul>
+<li><a href="{{.MetricsPath}}">Metrics</a></li>
+<li><a href="/-/status">Status (JSON)</a></li>
+<li><a href="/-/ready">Readiness</a></li>
+<li><a href="/-/healthy">Health</a></li>
+{{- if .DebugLastScrape}}
+<li><a href="/debug/last-scrape">Last scrape API payloads (redacted)</a></li>
+{{- end}}
+{{- if .TopArtifacts}}
+<li><a href="/top-artifacts">Top artifacts report (JSON)</a></li>
+{{- end}}
+</ul>
+<h2>Target</h2>
+<table>
+<tr><th align="left">Artifactory URI</th><td>{{.ScrapeURI}}</td></tr>
+<tr><th align="left">Auth method</th><td>{{.AuthMethod}}</td></tr>
+<tr><th align="left">Artifactory version</th><td>{{with .Status.ArtifactoryVersion}}{{.}}{{else}}unknown{{end}}</td></tr>
+<tr><th align="left">Ready</th><td>{{.Status.Ready}}</td></tr>
+<tr><th align="left">Last scrape</th><td>{{with .Status.LastScrape}}{{.Format "2006-01-02T15:04:05Z07:00"}}{{else}}never{{end}} ({{printf "%.3f" .Status.LastScrapeDuration}}s)</td></tr>
+</table>
+<h2>Collectors</h2>
+<table>
+<tr><th align="left">Collector</th><th align="left">Last success</th><th align="left">Duration</th><th align="left">Skipped</th><th align="left">Last error</th></tr>
+{{- range .Collectors}}
+<tr>
+<td>{{.Name}}</td>
+<td>{{with .Status.LastSuccess}}{{.Format "2006-01-02T15:04:05Z07:00"}}{{else}}never{{end}}</td>
+<td>{{printf "%.3f" .Status.LastDuration}}s</td>
+<td>{{.Status.LastSkipped}}</td>
+<td>{{.Status.LastError}}</td>
+</tr>
+{{- end}}
+</table>
+<h2>API response cache</h2>
+{{- if .Cache.Enabled}}
+<table>
+<tr><th align="left">Entries</th><td>{{.Cache.Entries}}</td></tr>
+<tr><th align="left">Revalidated (304 Not Modified)</th><td>{{.Cache.NotModified}}</td></tr>
+<tr><th align="left">Bytes saved</th><td>{{.Cache.BytesSaved}}</td></tr>
+</table>
+{{- else}}
+<p>Disabled, see <code>--use-cache</code>.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+type landingPageCollector struct {
+	Name   string
+	Status collector.CollectorStatus
+}
+
+type landingPageData struct {
+	Version         string
+	Revision        string
+	Branch          string
+	BuildDate       string
+	MetricsPath     string
+	ScrapeURI       string
+	AuthMethod      string
+	DebugLastScrape bool
+	TopArtifacts    bool
+	Status          collector.Status
+	Collectors      []landingPageCollector
+	Cache           artifactory.CacheStats
+}
+
+// displayURI returns the URI without the credentials it may embed.
+func displayURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "<invalid URI>"
+	}
+	u.User = nil
+	return u.String()
+}
+
+// landingPageHandler renders an overview of the exporter configuration and
+// collector state. Credentials are never rendered, only the auth method.
+func landingPageHandler(conf *config.Config, exporter *collector.Exporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		status := exporter.Status()
+		data := landingPageData{
+			Version:         version.Version,
+			Revision:        version.Revision,
+			Branch:          version.Branch,
+			BuildDate:       version.BuildDate,
+			MetricsPath:     conf.MetricsPath,
+			ScrapeURI:       displayURI(conf.ArtiScrapeURI),
+			AuthMethod:      conf.Credentials.AuthMethod,
+			DebugLastScrape: conf.DebugLastScrape,
+			TopArtifacts:    conf.ExporterRuntimeConfig.OptionalMetrics.TopArtifacts,
+			Status:          status,
+			Cache:           exporter.CacheStats(),
+		}
+		for _, name := range exporter.EnabledCollectors() {
+			data.Collectors = append(data.Collectors, landingPageCollector{
+				Name:   name,
+				Status: status.Collectors[name],
+			})
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := landingPageTemplate.Execute(w, data); err != nil {
+			conf.Logger.Error(
+				"Error rendering landing page",
+				"err", err.Error(),
+			)
+		}
+	}
+}